@@ -0,0 +1,213 @@
+package huobi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cast"
+)
+
+// /v1/order/batch-orders每次最多下单数量
+const BatchPlaceLimit = 10
+
+// 批量下单中一笔订单的结果, 与传入的订单一一对应
+type BatchPlaceResult struct {
+	Params  *PlaceRequestParams // 下单参数, 未指定ClientOrderID时为自动生成的
+	OrderID string              // 订单ID, 失败时为空
+	Err     error               // 本地校验错误、请求错误或火币返回的*APIError
+}
+
+func (r *BatchPlaceResult) OK() bool {
+	return r.Err == nil
+}
+
+// 批量下单, 超过BatchPlaceLimit时自动拆分为多次请求
+// 返回的结果与orders顺序一致, 每笔订单的成败见BatchPlaceResult.Err
+// 有请求整体失败时同时返回第一个请求错误, 该请求中的订单都以此错误作为结果
+// 未指定ClientOrderID的订单自动生成, 用来把返回结果对应到订单
+func (ex *Exchange) BatchPlace(orders []*PlaceRequestParams) ([]*BatchPlaceResult, error) {
+	results := make([]*BatchPlaceResult, len(orders))
+	var pending []*BatchPlaceResult
+	var batch []map[string]string
+	var firstErr error
+
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := ex.batchPlace(batch, pending); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		pending, batch = nil, nil
+	}
+
+	for i, order := range orders {
+		params := *order
+		if params.AccountID == "" {
+			params.AccountID = ex.accountId
+		}
+		if params.ClientOrderID == "" {
+			params.ClientOrderID = NewClientOrderID()
+		}
+		results[i] = &BatchPlaceResult{Params: &params}
+
+		mapParams, err := ex.placeParams(&params)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		pending = append(pending, results[i])
+		batch = append(batch, mapParams)
+		if len(batch) == BatchPlaceLimit {
+			flush()
+		}
+	}
+	flush()
+	return results, firstErr
+}
+
+// 发出一次批量下单请求, 按client-order-id把结果填入results
+func (ex *Exchange) batchPlace(batch []map[string]string, results []*BatchPlaceResult) error {
+	strRequest := "/v1/order/batch-orders"
+	jsonReturn, err := ex.ApiKeyPostBatchorder(batch, strRequest)
+	if err == nil {
+		batchReturn := &BatchPlaceReturn{}
+		if err = json.Unmarshal([]byte(jsonReturn), batchReturn); err == nil {
+			fillBatchPlaceResults(batchReturn.Data, results)
+			return nil
+		}
+	}
+	for _, result := range results {
+		result.Err = err
+	}
+	return err
+}
+
+func fillBatchPlaceResults(items []BatchPlaceItem, results []*BatchPlaceResult) {
+	byClientOrderID := make(map[string]*BatchPlaceResult, len(results))
+	for _, result := range results {
+		byClientOrderID[result.Params.ClientOrderID] = result
+	}
+	for i, item := range items {
+		result, ok := byClientOrderID[item.ClientOrderID]
+		if !ok {
+			// 没有返回client-order-id时按顺序对应
+			if i >= len(results) {
+				continue
+			}
+			result = results[i]
+		}
+		if item.ErrCode != "" {
+			result.Err = &APIError{HTTPStatus: http.StatusOK, Code: item.ErrCode, Message: item.ErrMsg}
+			continue
+		}
+		result.OrderID = cast.ToString(item.OrderID)
+	}
+	for _, result := range results {
+		if result.OrderID == "" && result.Err == nil {
+			result.Err = errors.New("huobi: no result for client-order-id " + result.Params.ClientOrderID)
+		}
+	}
+}
+
+// 按条件批量撤销挂单的过滤条件, 零值表示不限
+type CancelOpenOrdersFilter struct {
+	AccountID string      // 账户ID, 为空时使用Exchange的现货账户
+	Symbols   []string    // 交易对, 最多10个
+	Side      OrderSide   // 买卖方向
+	Types     []OrderType // 订单类型
+	Size      int         // 每次请求最多撤销的数量, 最大100, 默认100
+}
+
+// 批量撤单累计的成功和失败数量
+type BatchCancelCount struct {
+	SuccessCount int
+	FailedCount  int
+}
+
+// 按订单ID或用户自编订单号批量撤单每次最多的数量
+const BatchCancelLimit = 50
+
+// 按条件撤销挂单, 重复请求直到没有符合条件的挂单, 返回累计的成功和失败数量
+// 出错时返回已撤销的数量和错误
+func (ex *Exchange) BatchCancelOpenOrders(filter *CancelOpenOrdersFilter) (*BatchCancelCount, error) {
+	if filter == nil {
+		filter = &CancelOpenOrdersFilter{}
+	}
+	params := make(map[string]string)
+	params["account-id"] = filter.AccountID
+	if params["account-id"] == "" {
+		params["account-id"] = ex.accountId
+	}
+	if len(filter.Symbols) > 0 {
+		params["symbol"] = strings.Join(filter.Symbols, ",")
+	}
+	if filter.Side != "" {
+		params["side"] = string(filter.Side)
+	}
+	if len(filter.Types) > 0 {
+		types := make([]string, 0, len(filter.Types))
+		for _, orderType := range filter.Types {
+			types = append(types, string(orderType))
+		}
+		params["types"] = strings.Join(types, ",")
+	}
+	if filter.Size > 0 {
+		params["size"] = strconv.Itoa(filter.Size)
+	}
+
+	count := &BatchCancelCount{}
+	strRequest := "/v1/order/orders/batchCancelOpenOrders"
+	for {
+		jsonReturn, err := ex.ApiKeyPost(params, strRequest)
+		if err != nil {
+			return count, err
+		}
+		cancelReturn := &BatchCancelOpenReturn{}
+		if err := json.Unmarshal([]byte(jsonReturn), cancelReturn); err != nil {
+			return count, err
+		}
+		count.SuccessCount += cancelReturn.Data.SuccessCount
+		count.FailedCount += cancelReturn.Data.FailedCount
+		// 没有撤销任何订单时停止, 避免撤单一直失败时无限循环
+		if cancelReturn.Data.NextID == -1 || cancelReturn.Data.SuccessCount == 0 {
+			return count, nil
+		}
+	}
+}
+
+// 按订单ID批量撤单, 超过BatchCancelLimit时自动拆分为多次请求
+// 出错时返回已完成请求的结果和错误
+func (ex *Exchange) BatchCancelByIDs(orderIds []string) (*BatchCancelData, error) {
+	return ex.batchCancel("order-ids", orderIds)
+}
+
+// 按用户自编订单号批量撤单, 超过BatchCancelLimit时自动拆分为多次请求
+func (ex *Exchange) BatchCancelByClientIDs(clientOrderIds []string) (*BatchCancelData, error) {
+	return ex.batchCancel("client-order-ids", clientOrderIds)
+}
+
+func (ex *Exchange) batchCancel(key string, ids []string) (*BatchCancelData, error) {
+	result := &BatchCancelData{}
+	strRequest := "/v1/order/orders/batchcancel"
+	for start := 0; start < len(ids); start += BatchCancelLimit {
+		end := start + BatchCancelLimit
+		if end > len(ids) {
+			end = len(ids)
+		}
+		jsonReturn, err := ex.ApiKeyPostBatchorder(map[string]interface{}{key: ids[start:end]}, strRequest)
+		if err != nil {
+			return result, err
+		}
+		cancelReturn := &BatchCancelReturn{}
+		if err := json.Unmarshal([]byte(jsonReturn), cancelReturn); err != nil {
+			return result, err
+		}
+		result.Success = append(result.Success, cancelReturn.Data.Success...)
+		result.Failed = append(result.Failed, cancelReturn.Data.Failed...)
+	}
+	return result, nil
+}
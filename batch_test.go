@@ -0,0 +1,404 @@
+package huobi_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/monkeybang/huobi"
+	"github.com/monkeybang/huobi/huobitest"
+	"github.com/shopspring/decimal"
+)
+
+// 模拟服务拒绝无法解析的批量撤单请求, 而不是当作空列表返回成功
+func TestBatchCancelRejectsMalformedBody(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	for _, body := range []interface{}{"oops", map[string]interface{}{"order-ids": 1}} {
+		if _, err := ex.ApiKeyPostBatchorder(body, "/v1/order/orders/batchcancel"); !huobi.IsErrCode(err, huobi.ErrCodeInvalidParameter) {
+			t.Errorf("body %v: err = %v, want %s", body, err, huobi.ErrCodeInvalidParameter)
+		}
+	}
+}
+
+// 下n笔btcusdt限价买单的参数, bad为要被服务端拒绝的下标, 使用不存在的交易对
+func batchOrders(n int, bad ...int) []*huobi.PlaceRequestParams {
+	orders := make([]*huobi.PlaceRequestParams, n)
+	for i := range orders {
+		orders[i] = &huobi.PlaceRequestParams{
+			Symbol: "btcusdt",
+			Type:   huobi.OrderTypeBuyLimit,
+			Amount: decimal.New(int64(i+1), 0),
+			Price:  decimal.New(100, 0),
+			Source: "api",
+		}
+	}
+	for _, i := range bad {
+		orders[i].Symbol = "foousdt"
+	}
+	return orders
+}
+
+// 按client-order-id找出模拟服务中的订单ID
+func serverOrderIDs(server *huobitest.Server) map[string]string {
+	ids := make(map[string]string)
+	for _, order := range server.Orders() {
+		ids[order.ClientOrderID] = strconv.FormatInt(order.ID, 10)
+	}
+	return ids
+}
+
+func TestBatchPlaceSplitsAndMatchesResults(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	orders := batchOrders(23, 12)
+	orders[3].ClientOrderID = "mine"
+	results, err := ex.BatchPlace(orders)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var sizes []int
+	for _, request := range server.Requests() {
+		if request.Path == "/v1/order/batch-orders" {
+			sizes = append(sizes, len(request.Items))
+		}
+	}
+	if !reflect.DeepEqual(sizes, []int{10, 10, 3}) {
+		t.Errorf("request sizes = %v, want [10 10 3]", sizes)
+	}
+
+	ids := serverOrderIDs(server)
+	if len(results) != len(orders) {
+		t.Fatalf("got %d results, want %d", len(results), len(orders))
+	}
+	for i, result := range results {
+		if !result.Params.Amount.Equal(orders[i].Amount) || result.Params.ClientOrderID == "" {
+			t.Errorf("result %d params = %v, want amount %s", i, result.Params, orders[i].Amount)
+		}
+		if i == 12 {
+			if !huobi.IsErrCode(result.Err, "base-symbol-error") || result.OrderID != "" {
+				t.Errorf("rejected result = %q, %v, want base-symbol-error", result.OrderID, result.Err)
+			}
+			continue
+		}
+		if !result.OK() || result.OrderID != ids[result.Params.ClientOrderID] {
+			t.Errorf("result %d = %q, %v, want order %s", i, result.OrderID, result.Err, ids[result.Params.ClientOrderID])
+		}
+	}
+	if results[3].Params.ClientOrderID != "mine" {
+		t.Errorf("client-order-id = %s, want mine", results[3].Params.ClientOrderID)
+	}
+	if orders[0].ClientOrderID != "" {
+		t.Error("BatchPlace modified the caller's params")
+	}
+}
+
+// 一次请求整体失败时, 该请求中的订单都以此错误作为结果, 其他请求不受影响
+func TestBatchPlaceFailedRequest(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	server.FailNext(http.MethodPost, "/v1/order/batch-orders", 1, http.StatusOK, huobi.ErrCodeInternal, "internal error")
+	results, err := ex.BatchPlace(batchOrders(13))
+	if !huobi.IsErrCode(err, huobi.ErrCodeInternal) {
+		t.Fatalf("err = %v, want %s", err, huobi.ErrCodeInternal)
+	}
+	for i, result := range results {
+		if i < huobi.BatchPlaceLimit {
+			if result.Err != err || result.OrderID != "" {
+				t.Errorf("result %d = %q, %v, want request error", i, result.OrderID, result.Err)
+			}
+		} else if !result.OK() {
+			t.Errorf("result %d err = %v", i, result.Err)
+		}
+	}
+	if n := len(server.Orders()); n != 3 {
+		t.Errorf("server has %d orders, want 3", n)
+	}
+}
+
+// 服务端没有返回client-order-id时按顺序对应
+func TestBatchPlacePositionalResults(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	server.Handle(http.MethodPost, "/v1/order/batch-orders", func(w http.ResponseWriter, r *http.Request) {
+		requests := server.Requests()
+		items := requests[len(requests)-1].Items
+		data := make([]map[string]interface{}, 0, len(items))
+		for i, item := range items {
+			if item["symbol"] != "btcusdt" {
+				data = append(data, map[string]interface{}{"err-code": "base-symbol-error", "err-msg": "The symbol is invalid."})
+				continue
+			}
+			data = append(data, map[string]interface{}{"order-id": 100 + i})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "data": data})
+	})
+
+	results, err := ex.BatchPlace(batchOrders(4, 2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"100", "101", "", "103"}
+	for i, result := range results {
+		if result.OrderID != want[i] {
+			t.Errorf("result %d order = %q, want %q", i, result.OrderID, want[i])
+		}
+	}
+	if !huobi.IsErrCode(results[2].Err, "base-symbol-error") {
+		t.Errorf("rejected result err = %v", results[2].Err)
+	}
+}
+
+// 服务端返回的结果顺序与请求不同时按client-order-id对应
+func TestBatchPlaceResultsOutOfOrder(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	server.Handle(http.MethodPost, "/v1/order/batch-orders", func(w http.ResponseWriter, r *http.Request) {
+		requests := server.Requests()
+		items := requests[len(requests)-1].Items
+		data := make([]map[string]interface{}, 0, len(items))
+		for i := len(items) - 1; i >= 0; i-- {
+			data = append(data, map[string]interface{}{"client-order-id": items[i]["client-order-id"], "order-id": 100 + i})
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "data": data})
+	})
+
+	results, err := ex.BatchPlace(batchOrders(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, result := range results {
+		if want := strconv.Itoa(100 + i%huobi.BatchPlaceLimit); result.OrderID != want {
+			t.Errorf("result %d order = %q, want %q", i, result.OrderID, want)
+		}
+	}
+}
+
+func TestBatchPlaceRejectsMalformedBody(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	if _, err := ex.ApiKeyPostBatchorder(map[string]string{"symbol": "btcusdt"}, "/v1/order/batch-orders"); !huobi.IsErrCode(err, huobi.ErrCodeInvalidParameter) {
+		t.Errorf("err = %v, want %s", err, huobi.ErrCodeInvalidParameter)
+	}
+}
+
+// 下count笔symbol的orderType限价单
+func placeOrders(t *testing.T, ex *huobi.Exchange, symbol string, orderType huobi.OrderType, count int) []string {
+	t.Helper()
+	ids := make([]string, 0, count)
+	for i := 0; i < count; i++ {
+		orderID, err := ex.PlaceOrder(symbol, orderType, decimal.New(1, 0), decimal.New(100, 0))
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, orderID)
+	}
+	return ids
+}
+
+// 模拟服务中仍在挂单的订单ID
+func openOrderIDs(server *huobitest.Server) map[string]bool {
+	open := make(map[string]bool)
+	for _, order := range server.Orders() {
+		if order.State == huobitest.OrderStateSubmitted {
+			open[strconv.FormatInt(order.ID, 10)] = true
+		}
+	}
+	return open
+}
+
+func TestBatchCancelOpenOrdersFilters(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	placeOrders(t, ex, "btcusdt", huobi.OrderTypeBuyLimit, 25)
+	keep := placeOrders(t, ex, "btcusdt", huobi.OrderTypeSellLimit, 3)
+	keep = append(keep, placeOrders(t, ex, "ethusdt", huobi.OrderTypeBuyLimit, 3)...)
+	keep = append(keep, placeOrders(t, ex, "btcusdt", huobi.OrderTypeBuyLimitMaker, 3)...)
+
+	filter := &huobi.CancelOpenOrdersFilter{
+		Symbols: []string{"btcusdt"},
+		Side:    huobi.SideBuy,
+		Types:   []huobi.OrderType{huobi.OrderTypeBuyLimit},
+		Size:    10,
+	}
+	count, err := ex.BatchCancelOpenOrders(filter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count.SuccessCount != 25 || count.FailedCount != 0 {
+		t.Errorf("count = %+v, want 25 success", count)
+	}
+
+	// 每页最多撤销10笔, 第三页返回next-id为-1后停止
+	want := map[string]string{"account-id": ex.GetAccountId(), "symbol": "btcusdt", "side": "buy", "types": "buy-limit", "size": "10"}
+	requests := 0
+	for _, request := range server.Requests() {
+		if request.Path != "/v1/order/orders/batchCancelOpenOrders" {
+			continue
+		}
+		requests++
+		if !reflect.DeepEqual(request.Body, want) {
+			t.Errorf("request %d body = %v, want %v", requests, request.Body, want)
+		}
+	}
+	if requests != 3 {
+		t.Errorf("sent %d requests, want 3", requests)
+	}
+
+	open := openOrderIDs(server)
+	if len(open) != len(keep) {
+		t.Errorf("%d orders still open, want %d", len(open), len(keep))
+	}
+	for _, orderID := range keep {
+		if !open[orderID] {
+			t.Errorf("order %s canceled, should not match the filter", orderID)
+		}
+	}
+}
+
+// 没有撤销任何订单时停止, 即使服务端返回了next-id
+func TestBatchCancelOpenOrdersStopsWithoutProgress(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	server.SetResponse(http.MethodPost, "/v1/order/orders/batchCancelOpenOrders",
+		`{"status":"ok","data":{"success-count":0,"failed-count":3,"next-id":5}}`)
+	count, err := ex.BatchCancelOpenOrders(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count.SuccessCount != 0 || count.FailedCount != 3 {
+		t.Errorf("count = %+v, want 3 failed", count)
+	}
+	if n := countRequests(server, http.MethodPost, "/v1/order/orders/batchCancelOpenOrders"); n != 1 {
+		t.Errorf("sent %d requests, want 1", n)
+	}
+}
+
+// 每次请求中key对应的数组长度
+func batchCancelSizes(server *huobitest.Server, key string) []int {
+	var sizes []int
+	for _, request := range server.Requests() {
+		if request.Path == "/v1/order/orders/batchcancel" {
+			sizes = append(sizes, len(strings.Fields(strings.Trim(request.Body[key], "[]"))))
+		}
+	}
+	return sizes
+}
+
+func TestBatchCancelByIDsSplits(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	ids := placeOrders(t, ex, "btcusdt", huobi.OrderTypeBuyLimit, 110)
+	for i := 0; i < 10; i++ {
+		ids = append(ids, strconv.Itoa(900000+i))
+	}
+	result, err := ex.BatchCancelByIDs(ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizes := batchCancelSizes(server, "order-ids"); !reflect.DeepEqual(sizes, []int{50, 50, 20}) {
+		t.Errorf("request sizes = %v, want [50 50 20]", sizes)
+	}
+	if !reflect.DeepEqual(result.Success, ids[:110]) {
+		t.Errorf("success = %v, want %v", result.Success, ids[:110])
+	}
+	if len(result.Failed) != 10 || result.Failed[0].OrderID != ids[110] || result.Failed[0].OrderState != -1 {
+		t.Errorf("failed = %+v, want the 10 unknown orders", result.Failed)
+	}
+	if open := openOrderIDs(server); len(open) != 0 {
+		t.Errorf("%d orders still open", len(open))
+	}
+}
+
+func TestBatchCancelByClientIDsSplits(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	results, err := ex.BatchPlace(batchOrders(60))
+	if err != nil {
+		t.Fatal(err)
+	}
+	clientIDs := make([]string, 0, len(results))
+	for _, result := range results {
+		clientIDs = append(clientIDs, result.Params.ClientOrderID)
+	}
+	// 已撤销的订单再撤一次时失败
+	if _, err := ex.CancelOrder(results[0].OrderID); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := ex.BatchCancelByClientIDs(clientIDs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sizes := batchCancelSizes(server, "client-order-ids"); !reflect.DeepEqual(sizes, []int{50, 10}) {
+		t.Errorf("request sizes = %v, want [50 10]", sizes)
+	}
+	if len(result.Success) != 59 || len(result.Failed) != 1 || result.Failed[0].ClientOrderID != clientIDs[0] {
+		t.Errorf("result = %d success, failed %+v, want 59 success and %s failed", len(result.Success), result.Failed, clientIDs[0])
+	}
+}
+
+// 请求出错时返回已完成请求的结果
+func TestBatchCancelByIDsFailedRequest(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	ids := placeOrders(t, ex, "btcusdt", huobi.OrderTypeBuyLimit, 60)
+	path := "/v1/order/orders/batchcancel"
+	// 第一次请求撤销成功, 第二次请求返回502
+	server.Handle(http.MethodPost, path, func(w http.ResponseWriter, r *http.Request) {
+		if countRequests(server, http.MethodPost, path) == 2 {
+			w.WriteHeader(http.StatusBadGateway)
+			fmt.Fprint(w, `{"status":"error","err-code":"gateway-internal-error","err-msg":"internal error"}`)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "data": map[string]interface{}{"success": ids[:huobi.BatchCancelLimit]}})
+	})
+	result, err := ex.BatchCancelByIDs(ids)
+	if !huobi.IsErrCode(err, huobi.ErrCodeInternal) {
+		t.Errorf("err = %v, want %s", err, huobi.ErrCodeInternal)
+	}
+	if len(result.Success) != huobi.BatchCancelLimit {
+		t.Errorf("got %d successes, want the first chunk of %d", len(result.Success), huobi.BatchCancelLimit)
+	}
+	if n := countRequests(server, http.MethodPost, path); n != 2 {
+		t.Errorf("sent %d requests, want 2", n)
+	}
+}
@@ -0,0 +1,95 @@
+package huobi
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 本地时钟相对服务器的偏差, 签名时用来修正时间戳
+// 同一Exchange的副本(WithContext等)共享同一个clock
+type clock struct {
+	offset   int64 // 服务器时间减本地时间, 单位纳秒
+	interval time.Duration
+
+	mu   sync.Mutex
+	stop chan struct{}
+}
+
+// 构造时与服务器对时, 并每隔interval重新对时, 需调用Close停止
+// 服务器时间取自GetTimestamp(/v1/common/timestamp)
+func WithClockSync(interval time.Duration) Option {
+	return func(ex *Exchange) {
+		ex.clock.interval = interval
+	}
+}
+
+// 以服务器时间为准的当前时间
+func (ex *Exchange) now() time.Time {
+	return time.Now().Add(ex.ClockSkew())
+}
+
+// 签名使用的时间戳
+func (ex *Exchange) timestamp() string {
+	return ex.now().UTC().Format("2006-01-02T15:04:05")
+}
+
+// 最近一次对时测得的偏差, 正数表示本地时钟比服务器慢
+func (ex *Exchange) ClockSkew() time.Duration {
+	return time.Duration(atomic.LoadInt64(&ex.clock.offset))
+}
+
+// 与服务器对时一次, 返回测得的偏差
+// 以请求往返的中点作为服务器生成时间戳的本地时刻
+func (ex *Exchange) SyncTime() (time.Duration, error) {
+	start := time.Now()
+	timestampReturn, err := ex.GetTimestamp()
+	if err != nil {
+		return ex.ClockSkew(), err
+	}
+	end := time.Now()
+
+	serverTime := time.Unix(0, timestampReturn.Data*int64(time.Millisecond))
+	localTime := start.Add(end.Sub(start) / 2)
+	offset := serverTime.Sub(localTime)
+	atomic.StoreInt64(&ex.clock.offset, int64(offset))
+	return offset, nil
+}
+
+// 启动后台定时对时, 已启动或未设置间隔时不做任何事
+func (ex *Exchange) startClockSync() {
+	c := ex.clock
+	if c.interval <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		return
+	}
+	c.stop = make(chan struct{})
+
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if _, err := ex.SyncTime(); err != nil {
+					ex.log().Warn("sync time error", "error", err)
+				}
+			}
+		}
+	}(c.stop)
+}
+
+func (c *clock) close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+}
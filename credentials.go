@@ -0,0 +1,140 @@
+package huobi
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os"
+)
+
+// 一对API密钥, SecretKey用字节切片保存, 用完后可以清零
+type Credentials struct {
+	AccessKey string
+	SecretKey []byte
+}
+
+// 把SecretKey清零, 避免密钥留在内存中
+func (c *Credentials) Zero() {
+	if c == nil {
+		return
+	}
+	for i := range c.SecretKey {
+		c.SecretKey[i] = 0
+	}
+	c.SecretKey = nil
+}
+
+// 密钥来源, Exchange在每次签名时调用Retrieve取出密钥, 签名后把取出的密钥清零
+// 因此Retrieve每次都应返回新的副本
+type CredentialsProvider interface {
+	Retrieve() (*Credentials, error)
+}
+
+// 以函数作为密钥来源, 如从密钥管理服务获取
+type CredentialsFunc func() (*Credentials, error)
+
+func (f CredentialsFunc) Retrieve() (*Credentials, error) {
+	return f()
+}
+
+// 固定的密钥
+type StaticCredentials struct {
+	accessKey string
+	secretKey []byte
+}
+
+func NewStaticCredentials(accessKey, secretKey string) *StaticCredentials {
+	return &StaticCredentials{accessKey: accessKey, secretKey: []byte(secretKey)}
+}
+
+func (s *StaticCredentials) Retrieve() (*Credentials, error) {
+	secretKey := make([]byte, len(s.secretKey))
+	copy(secretKey, s.secretKey)
+	return &Credentials{AccessKey: s.accessKey, SecretKey: secretKey}, nil
+}
+
+// 从环境变量读取密钥, 默认变量名为HUOBI_ACCESS_KEY和HUOBI_SECRET_KEY
+// 通过WithSigner使用非对称签名时可以不设置SecretKey
+type EnvCredentials struct {
+	AccessKeyVar string
+	SecretKeyVar string
+}
+
+func NewEnvCredentials() *EnvCredentials {
+	return &EnvCredentials{AccessKeyVar: "HUOBI_ACCESS_KEY", SecretKeyVar: "HUOBI_SECRET_KEY"}
+}
+
+func (e *EnvCredentials) Retrieve() (*Credentials, error) {
+	accessKey := os.Getenv(e.AccessKeyVar)
+	secretKey := os.Getenv(e.SecretKeyVar)
+	if accessKey == "" {
+		return nil, errors.New("huobi: " + e.AccessKeyVar + " not set")
+	}
+	return &Credentials{AccessKey: accessKey, SecretKey: []byte(secretKey)}, nil
+}
+
+// 从JSON文件读取密钥, 每次签名时重新读取, 便于在不重启的情况下更换密钥
+// 文件格式: {"accessKey":"...","secretKey":"..."}, 使用非对称签名时可以省略secretKey
+type FileCredentials struct {
+	Path string
+}
+
+func NewFileCredentials(path string) *FileCredentials {
+	return &FileCredentials{Path: path}
+}
+
+func (f *FileCredentials) Retrieve() (*Credentials, error) {
+	bytes, err := ioutil.ReadFile(f.Path)
+	if err != nil {
+		return nil, err
+	}
+	defer zeroBytes(bytes)
+
+	var file struct {
+		AccessKey string `json:"accessKey"`
+		SecretKey string `json:"secretKey"`
+	}
+	if err := json.Unmarshal(bytes, &file); err != nil {
+		return nil, err
+	}
+	if file.AccessKey == "" {
+		return nil, errors.New("huobi: accessKey missing in " + f.Path)
+	}
+	return &Credentials{AccessKey: file.AccessKey, SecretKey: []byte(file.SecretKey)}, nil
+}
+
+func zeroBytes(bytes []byte) {
+	for i := range bytes {
+		bytes[i] = 0
+	}
+}
+
+// 使用指定的密钥来源, 替代构造函数中传入的ak, sk
+func WithCredentials(provider CredentialsProvider) Option {
+	return func(ex *Exchange) {
+		ex.credentials = provider
+	}
+}
+
+// 取出密钥并用它签名, fn返回后立即清零密钥
+// 指定了Signer时用Signer签名, 否则用SecretKey做HmacSHA256签名
+// 构造时加载失败的, 直接返回构造时的错误, 避免以空的account-id下单或查询
+func (ex *Exchange) withSigner(fn func(accessKey string, signer Signer) error) error {
+	if ex.initErr != nil {
+		return ex.initErr
+	}
+	credentials, err := ex.credentials.Retrieve()
+	if err != nil {
+		return err
+	}
+	defer credentials.Zero()
+
+	signer := ex.signer
+	if signer == nil {
+		if len(credentials.SecretKey) == 0 {
+			return ErrNoSecretKey
+		}
+		signer = &HmacSigner{secretKey: credentials.SecretKey}
+	}
+	return fn(credentials.AccessKey, signer)
+}
@@ -1,6 +1,15 @@
 package huobi
 
 import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+
 	"github.com/dgrijalva/jwt-go"
 )
 
@@ -13,3 +22,99 @@ func SignByJWT(pem string, data string) (string, error) {
 	alg := jwt.GetSigningMethod("ES256")
 	return alg.Sign(data, key)
 }
+
+// 签名方式, 构造Exchange时通过WithSigner选择, 默认为HmacSHA256
+type Signer interface {
+	// 填入SignatureMethod参数的值
+	SignatureMethod() string
+	// 对CreatePayload生成的字符串签名, 返回填入Signature参数的值
+	Sign(payload string) (string, error)
+}
+
+// HmacSHA256签名, 密钥与交易所共享
+// 未通过WithSigner指定签名方式时, Exchange每次签名都用CredentialsProvider取出的密钥创建它
+type HmacSigner struct {
+	secretKey []byte
+}
+
+func NewHmacSigner(secretKey string) *HmacSigner {
+	return &HmacSigner{secretKey: []byte(secretKey)}
+}
+
+func (s *HmacSigner) SignatureMethod() string {
+	return "HmacSHA256"
+}
+
+func (s *HmacSigner) Sign(payload string) (string, error) {
+	return computeHmac256(payload, s.secretKey), nil
+}
+
+// ECDSA(ES256)签名, 私钥只保存在本地
+type ECDSASigner struct {
+	key *ecdsa.PrivateKey
+}
+
+// 从PEM格式的EC私钥创建ECDSASigner
+func NewECDSASigner(pemKey string) (*ECDSASigner, error) {
+	key, err := jwt.ParseECPrivateKeyFromPEM([]byte(pemKey))
+	if err != nil {
+		return nil, err
+	}
+	return &ECDSASigner{key: key}, nil
+}
+
+func (s *ECDSASigner) SignatureMethod() string {
+	return "ES256"
+}
+
+// 对payload的SHA-256摘要签名, 签名为32字节的R和32字节的S拼接后的标准Base64, 与其他Signer的格式一致
+func (s *ECDSASigner) Sign(payload string) (string, error) {
+	digest := sha256.Sum256([]byte(payload))
+	r, ss, err := ecdsa.Sign(rand.Reader, s.key, digest[:])
+	if err != nil {
+		return "", err
+	}
+	size := (s.key.Curve.Params().BitSize + 7) / 8
+	signature := make([]byte, 2*size)
+	rBytes, sBytes := r.Bytes(), ss.Bytes()
+	copy(signature[size-len(rBytes):size], rBytes)
+	copy(signature[2*size-len(sBytes):], sBytes)
+	return base64.StdEncoding.EncodeToString(signature), nil
+}
+
+// Ed25519签名, 私钥只保存在本地
+type Ed25519Signer struct {
+	key ed25519.PrivateKey
+}
+
+// 从PEM格式(PKCS#8)的Ed25519私钥创建Ed25519Signer
+func NewEd25519Signer(pemKey string) (*Ed25519Signer, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("huobi: invalid ed25519 pem key")
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, err
+	}
+	edKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, errors.New("huobi: not an ed25519 private key")
+	}
+	return &Ed25519Signer{key: edKey}, nil
+}
+
+func (s *Ed25519Signer) SignatureMethod() string {
+	return "Ed25519"
+}
+
+func (s *Ed25519Signer) Sign(payload string) (string, error) {
+	return base64.StdEncoding.EncodeToString(ed25519.Sign(s.key, []byte(payload))), nil
+}
+
+// 使用指定的签名方式, 如NewECDSASigner创建的非对称密钥签名
+func WithSigner(signer Signer) Option {
+	return func(ex *Exchange) {
+		ex.signer = signer
+	}
+}
@@ -0,0 +1,71 @@
+package huobi
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"os"
+	"testing"
+)
+
+func TestECDSASignerSign(t *testing.T) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := NewECDSASigner(string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: der})))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := CreatePayload(docSignParams, "GET", "api.huobi.pro", "/v1/order/orders")
+	// 多签几次, 覆盖R或S高位为0时的补齐
+	for i := 0; i < 50; i++ {
+		signature, err := signer.Sign(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := base64.StdEncoding.DecodeString(signature)
+		if err != nil {
+			t.Fatalf("signature %q is not standard base64: %v", signature, err)
+		}
+		if len(raw) != 64 {
+			t.Fatalf("signature length = %d, want 64", len(raw))
+		}
+		digest := sha256.Sum256([]byte(payload))
+		r, s := new(big.Int).SetBytes(raw[:32]), new(big.Int).SetBytes(raw[32:])
+		if !ecdsa.Verify(&key.PublicKey, digest[:], r, s) {
+			t.Fatalf("signature %q does not verify", signature)
+		}
+	}
+}
+
+func TestSignerWithoutSecretKey(t *testing.T) {
+	os.Setenv("HUOBI_TEST_ACCESS_KEY", "ak")
+	defer os.Unsetenv("HUOBI_TEST_ACCESS_KEY")
+	provider := &EnvCredentials{AccessKeyVar: "HUOBI_TEST_ACCESS_KEY", SecretKeyVar: "HUOBI_TEST_SECRET_KEY"}
+
+	ex := newExchange("", "", WithCredentials(provider))
+	params := make(map[string]string)
+	if err := ex.signParams(params, "GET", "api.huobi.pro", "/v1/account/accounts"); !errors.Is(err, ErrNoSecretKey) {
+		t.Errorf("hmac without secret key: err = %v, want ErrNoSecretKey", err)
+	}
+
+	ex = newExchange("", "", WithCredentials(provider), WithSigner(&recordSigner{}))
+	if err := ex.signParams(params, "GET", "api.huobi.pro", "/v1/account/accounts"); err != nil {
+		t.Fatalf("signer without secret key: %v", err)
+	}
+	if params["AccessKeyId"] != "ak" || params["Signature"] != "signature" {
+		t.Errorf("signed params = %v", params)
+	}
+}
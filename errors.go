@@ -0,0 +1,115 @@
+package huobi
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/tidwall/gjson"
+)
+
+// 常见的火币错误码
+const (
+	ErrCodeSignatureNotValid   = "api-signature-not-valid"                   // 签名错误
+	ErrCodeLoginRequired       = "login-required"                            // 缺少签名或AccessKeyId无效
+	ErrCodeInvalidParameter    = "invalid-parameter"                         // 参数错误
+	ErrCodeBalanceInsufficient = "account-frozen-balance-insufficient-error" // 余额不足
+	ErrCodeAccountBalance      = "order-accountbalance-error"                // 账户余额不足
+	ErrCodeTooManyRequests     = "too-many-requests"                         // 请求过于频繁
+	ErrCodeOrderNotFound       = "base-record-invalid"                       // 记录不存在
+	ErrCodeInternal            = "gateway-internal-error"                    // 服务端内部错误
+)
+
+var (
+	ErrUnknownSymbol = errors.New("huobi: unknown symbol")         // 交易对不存在或未加载
+	ErrNoSpotAccount = errors.New("huobi: spot account not found") // 账户列表中没有现货账户
+	ErrNoSecretKey   = errors.New("huobi: secret key required")    // 未指定Signer时HmacSHA256签名需要SecretKey
+)
+
+// 火币接口返回的错误
+// v1接口: {"status":"error","err-code":"...","err-msg":"..."}
+// v2接口: {"code":1002,"message":"..."}
+// 合约接口: {"status":"error","err_code":1032,"err_msg":"..."}
+type APIError struct {
+	HTTPStatus int    // HTTP状态码
+	Code       string // err-code, code或err_code, 统一转为字符串
+	Message    string // err-msg, message或err_msg
+	Body       string // 原始响应内容
+}
+
+func (e *APIError) Error() string {
+	if e.Code == "" && e.Message == "" {
+		return fmt.Sprintf("huobi: http %d: %s", e.HTTPStatus, e.Body)
+	}
+	return fmt.Sprintf("huobi: http %d: %s: %s", e.HTTPStatus, e.Code, e.Message)
+}
+
+// 是否余额不足
+func (e *APIError) IsInsufficientBalance() bool {
+	return e.Code == ErrCodeBalanceInsufficient || e.Code == ErrCodeAccountBalance
+}
+
+// 是否签名或密钥错误
+func (e *APIError) IsSignatureInvalid() bool {
+	return e.Code == ErrCodeSignatureNotValid || e.Code == ErrCodeLoginRequired
+}
+
+// 是否被限频, 包括HTTP 429以及合约接口的1032错误
+func (e *APIError) IsRateLimited() bool {
+	return e.HTTPStatus == http.StatusTooManyRequests || e.Code == ErrCodeTooManyRequests || e.Code == "1032"
+}
+
+// 取出err中的APIError, 不是火币接口错误时返回nil
+func AsAPIError(err error) *APIError {
+	var apiErr *APIError
+	if errors.As(err, &apiErr) {
+		return apiErr
+	}
+	return nil
+}
+
+// err是否为指定错误码之一的APIError
+func IsErrCode(err error, codes ...string) bool {
+	apiErr := AsAPIError(err)
+	if apiErr == nil {
+		return false
+	}
+	for _, code := range codes {
+		if apiErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// 检查响应内容, HTTP状态码异常或者返回体表明失败时返回APIError
+func checkResponse(httpStatus int, body string) error {
+	if !gjson.Valid(body) {
+		if httpStatus >= http.StatusBadRequest {
+			return &APIError{HTTPStatus: httpStatus, Body: body}
+		}
+		return &APIError{HTTPStatus: httpStatus, Message: "invalid json response", Body: body}
+	}
+
+	result := gjson.Parse(body)
+	apiErr := &APIError{HTTPStatus: httpStatus, Body: body}
+	if result.Get("status").String() == "error" {
+		if code := result.Get("err-code"); code.Exists() {
+			apiErr.Code = code.String()
+			apiErr.Message = result.Get("err-msg").String()
+		} else {
+			apiErr.Code = result.Get("err_code").String()
+			apiErr.Message = result.Get("err_msg").String()
+		}
+		return apiErr
+	}
+	if code := result.Get("code"); code.Exists() && code.Int() != http.StatusOK {
+		apiErr.Code = code.String()
+		apiErr.Message = result.Get("message").String()
+		return apiErr
+	}
+	if httpStatus >= http.StatusBadRequest {
+		return apiErr
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package huobi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/monkeybang/huobi"
+	"github.com/monkeybang/huobi/huobitest"
+)
+
+// 打开连接到模拟服务的Exchange, 调用方需Close
+func openExchange(t *testing.T, server *huobitest.Server, opts ...huobi.Option) *huobi.Exchange {
+	t.Helper()
+	ex, err := huobi.OpenExchange("ak", "sk", append(server.Options(), opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ex
+}
+
+func TestNewExchangeKeepsInitErr(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	server.FailNext(http.MethodGet, "/v1/account/accounts", 1, http.StatusOK, huobi.ErrCodeLoginRequired, "invalid access key")
+
+	ex := huobi.NewExchangeWithOptions("ak", "sk", server.Options()...)
+	defer ex.Close()
+	if !huobi.IsErrCode(ex.InitErr(), huobi.ErrCodeLoginRequired) {
+		t.Fatalf("InitErr = %v, want %s", ex.InitErr(), huobi.ErrCodeLoginRequired)
+	}
+
+	before := len(server.Requests())
+	if _, err := ex.OpenOrders("btcusdt"); err != ex.InitErr() {
+		t.Errorf("OpenOrders err = %v, want %v", err, ex.InitErr())
+	}
+	if n := len(server.Requests()) - before; n != 0 {
+		t.Errorf("sent %d requests after failed init, want 0", n)
+	}
+
+	ex = huobi.NewExchangeWithOptions("ak", "sk", server.Options()...)
+	defer ex.Close()
+	if ex.InitErr() != nil {
+		t.Errorf("InitErr = %v, want nil", ex.InitErr())
+	}
+}
@@ -4,6 +4,7 @@ go 1.13
 
 require (
 	github.com/dgrijalva/jwt-go v3.2.0+incompatible
+	github.com/shopspring/decimal v1.3.1
 	github.com/spf13/cast v1.3.1
 	github.com/tidwall/gjson v1.6.0
 )
@@ -1,298 +1,360 @@
 package huobi
 
 import (
+	"context"
+	cryptorand "crypto/rand"
 	"encoding/json"
-	"errors"
+	"fmt"
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cast"
 	"github.com/tidwall/gjson"
-	"log"
-	"math"
+	"net/http"
+	"sync/atomic"
 	"time"
 )
 
 type Exchange struct {
 	name      string
 	accountId string
-	accessKey string
-	secretKey string
-	symbols   map[string]*SymbolsData
+	symbols   *SymbolRegistry
+	endpoints Endpoints
+
+	httpClient  *http.Client
+	ctx         context.Context
+	rateLimiter RateLimiter
+	retryPolicy *RetryPolicy
+	clock       *clock
+	middlewares []Middleware
+	logger      Logger
+	signer      Signer
+	credentials CredentialsProvider
+	validator   *OrderValidator
+	initErr     error // NewExchange加载交易对或账户失败的错误, 签名请求直接返回该错误
 }
 
+// 使用默认选项构造Exchange, 加载交易对和现货账户ID失败时不返回错误
+// 失败后签名请求都返回该错误, 可通过InitErr查看; 需要在构造时检查错误的请使用OpenExchange
 func NewExchange(ak, sk string) *Exchange {
-	ex := &Exchange{
-		accessKey: ak,
-		secretKey: sk,
-	}
-	ex.name = "huobi"
-	ex.symbols = ex.GetSymbols()
-	accounts := ex.GetAccounts()
-	for _, data := range accounts.Data {
-		if data.Type == `spot` {
-			ex.accountId = cast.ToString(data.ID)
-		}
-	}
-	return ex
+	return NewExchangeWithOptions(ak, sk)
 }
 
-func (huobi *Exchange) GetSymbols() map[string]*SymbolsData {
+func (huobi *Exchange) GetSymbols() (map[string]*SymbolsData, error) {
 	symbolMap := make(map[string]*SymbolsData)
-	symbolsReturn := GetSymbols()
-	if symbolsReturn.Status == "ok" {
-		for i := range symbolsReturn.Data {
-			symbolMap[symbolsReturn.Data[i].BaseCurrency+symbolsReturn.Data[i].QuoteCurrency] = symbolsReturn.Data[i]
-		}
+	symbolsReturn, err := huobi.QuerySymbols()
+	if err != nil {
+		return symbolMap, err
 	}
-	return symbolMap
+	for i := range symbolsReturn.Data {
+		symbolMap[symbolsReturn.Data[i].Name()] = symbolsReturn.Data[i]
+	}
+	return symbolMap, nil
 }
 
-func (huobi *Exchange) TruncPrice(symbol string, price float64) (float64, bool) {
-	if data, ok := huobi.symbols[symbol]; ok == true {
-		pre := math.Pow10(data.PricePrecision)
-		tPrice := math.Round(price*pre) / pre
-		return tPrice, true
+// 按交易对的价格精度四舍五入, 交易对未知时返回false
+func (huobi *Exchange) TruncPrice(symbol string, price decimal.Decimal) (decimal.Decimal, bool) {
+	if data, ok := huobi.symbols.Get(symbol); ok == true {
+		return data.TruncPrice(price), true
 	}
-	return 0, false
+	return decimal.Zero, false
 }
 
-func (huobi *Exchange) TruncAmount(symbol string, amount float64) (float64, bool) {
-	if data, ok := huobi.symbols[symbol]; ok == true {
-		pre := math.Pow10(data.AmountPrecision)
-		amount := math.Trunc(amount*pre) / pre
-		return amount, true
+// 按交易对的数量精度截断, 交易对未知时返回false
+func (huobi *Exchange) TruncAmount(symbol string, amount decimal.Decimal) (decimal.Decimal, bool) {
+	if data, ok := huobi.symbols.Get(symbol); ok == true {
+		return data.TruncAmount(amount), true
 	}
-	return 0, false
+	return decimal.Zero, false
 }
 
-func (huobi *Exchange) Trunc(symbol string, price float64, amount float64) (float64, float64) {
-	truncPrice, err1 := huobi.TruncPrice(symbol, price)
-	truncAmount, err2 := huobi.TruncAmount(symbol, amount)
-	if err1 == false || err2 == false {
-		log.Fatal(err1, err2)
+func (huobi *Exchange) Trunc(symbol string, price decimal.Decimal, amount decimal.Decimal) (decimal.Decimal, decimal.Decimal, error) {
+	truncPrice, ok1 := huobi.TruncPrice(symbol, price)
+	truncAmount, ok2 := huobi.TruncAmount(symbol, amount)
+	if !ok1 || !ok2 {
+		return decimal.Zero, decimal.Zero, fmt.Errorf("%w: %s", ErrUnknownSymbol, symbol)
 	}
-	return truncPrice, truncAmount
+	return truncPrice, truncAmount, nil
 }
 
-func (huobi *Exchange) BuyLimitEver(symbol string, amount float64, price float64) (string, error) {
-	placeParams := &PlaceRequestParams{}
-	placeParams.AccountID = huobi.accountId
-	placeParams.Amount = cast.ToString(amount)
-	placeParams.Price = cast.ToString(price)
-	placeParams.Source = "api"
-	placeParams.Symbol = symbol
-	placeParams.Type = "buy-limit"
-
-	times := 20
-	for times > 0 {
-		times--
-		placeReturn := huobi.Place(placeParams)
-		if placeReturn.Status == "ok" {
-			//log.Println("Place return:", placeReturn.Data)
-			return placeReturn.Data, nil
-		} else {
-			log.Println("place error:", placeReturn.ErrMsg, amount, price)
-			time.Sleep(time.Millisecond * 100)
-		}
-	}
-	return "", errors.New("buy failed")
+// BuyLimitEver/SellLimitEver下单失败时使用的重试策略
+var LimitEverRetryPolicy = &RetryPolicy{
+	MaxAttempts:    20,
+	InitialBackoff: 100 * time.Millisecond,
+	MaxBackoff:     2 * time.Second,
+	Multiplier:     1.5,
+	Jitter:         0.2,
 }
 
-func (huobi *Exchange) SellLimitEver(symbol string, amount float64, price float64) (string, error) {
+func (huobi *Exchange) BuyLimitEver(symbol string, amount decimal.Decimal, price decimal.Decimal) (string, error) {
+	return huobi.placeLimitEver(symbol, OrderTypeBuyLimit, amount, price)
+}
+
+func (huobi *Exchange) SellLimitEver(symbol string, amount decimal.Decimal, price decimal.Decimal) (string, error) {
+	return huobi.placeLimitEver(symbol, OrderTypeSellLimit, amount, price)
+}
+
+// 按LimitEverRetryPolicy下限价单, 余额不足等不可重试的错误直接返回
+func (huobi *Exchange) placeLimitEver(symbol string, orderType OrderType, amount decimal.Decimal, price decimal.Decimal) (string, error) {
 	placeParams := &PlaceRequestParams{}
 	placeParams.AccountID = huobi.accountId
-	placeParams.Amount = cast.ToString(amount)
-	placeParams.Price = cast.ToString(price)
+	placeParams.Amount = amount
+	placeParams.Price = price
 	placeParams.Source = "api"
 	placeParams.Symbol = symbol
-	placeParams.Type = "sell-limit"
-
-	times := 20
-	for times > 0 {
-		times--
-		placeReturn := huobi.Place(placeParams)
-		if placeReturn.Status == "ok" {
-			//log.Println("Place return:", placeReturn.Data)
-			return placeReturn.Data, nil
-		} else {
-			log.Println("place error:", placeReturn.ErrMsg, amount, price, symbol)
-			time.Sleep(time.Millisecond * 100)
-		}
+	placeParams.Type = orderType
+
+	return huobi.PlaceWithRetry(placeParams, LimitEverRetryPolicy)
+}
+
+// 按policy重试下单, 保证同一笔订单最多成交一次
+// 未指定ClientOrderID时自动生成, 重试前先按ClientOrderID查询, 已下单成功则直接返回订单ID
+func (ex *Exchange) PlaceWithRetry(placeParams *PlaceRequestParams, policy *RetryPolicy) (string, error) {
+	params := *placeParams
+	if params.ClientOrderID == "" {
+		params.ClientOrderID = NewClientOrderID()
 	}
-	return "", errors.New("buy failed")
+
+	var orderId string
+	attempts := 0
+	err := policy.Do(ex.Context(), func() error {
+		attempts++
+		if attempts > 1 {
+			order, err := ex.GetClientOrder(params.ClientOrderID)
+			if err == nil {
+				orderId = cast.ToString(order.ID)
+				return nil
+			}
+			if !IsErrCode(err, ErrCodeOrderNotFound) {
+				return err
+			}
+		}
+
+		placeReturn, err := ex.Place(&params)
+		if err != nil {
+			ex.log().Warn("place error", "error", err, "symbol", params.Symbol, "type", params.Type,
+				"amount", params.Amount, "price", params.Price, "client-order-id", params.ClientOrderID)
+			return err
+		}
+		orderId = placeReturn.Data
+		return nil
+	})
+	return orderId, err
+}
+
+var clientOrderSeq uint32
+
+// 生成用户自编订单号, 由纳秒时间戳、进程内序号和随机数组成
+func NewClientOrderID() string {
+	var random [4]byte
+	_, _ = cryptorand.Read(random[:])
+	seq := atomic.AddUint32(&clientOrderSeq, 1)
+	return fmt.Sprintf("%x%04x%x", time.Now().UnixNano(), seq&0xffff, random)
 }
 
-func (huobi *Exchange) PlaceOrder(symbol string, orderType string, amount float64, price float64) {
+func (huobi *Exchange) PlaceOrder(symbol string, orderType OrderType, amount decimal.Decimal, price decimal.Decimal) (string, error) {
 	placeParams := &PlaceRequestParams{}
 	placeParams.AccountID = huobi.accountId
-	placeParams.Amount = cast.ToString(amount)
-	placeParams.Price = cast.ToString(price)
+	placeParams.Amount = amount
+	placeParams.Price = price
 	placeParams.Source = "api"
 	placeParams.Symbol = symbol
 	placeParams.Type = orderType
 
-	placeReturn := huobi.Place(placeParams)
-	if placeReturn.Status == "ok" {
-		log.Print("Place return :", placeReturn.Data)
-	} else {
-		log.Println("place error:", placeReturn.ErrMsg, placeReturn, symbol, orderType, amount, price)
+	placeReturn, err := huobi.Place(placeParams)
+	if err != nil {
+		return "", err
 	}
+	return placeReturn.Data, nil
 }
 
-func (ex *Exchange) BatchCancelOrders(symbol string) {
-	params := make(map[string]string)
-	params["account-id"] = ex.accountId
-	params["symbol"] = symbol
-
-	strRequest := "/v1/order/orders/batchCancelOpenOrders"
-	jsonPlaceReturn := ex.ApiKeyPost(make(map[string]string), strRequest)
-	log.Print(jsonPlaceReturn)
+// 撤销symbol的所有挂单, symbol为空时撤销现货账户的所有挂单
+func (ex *Exchange) BatchCancelOrders(symbol string) (*BatchCancelCount, error) {
+	filter := &CancelOpenOrdersFilter{}
+	if symbol != "" {
+		filter.Symbols = []string{symbol}
+	}
+	return ex.BatchCancelOpenOrders(filter)
 }
 
 func (huobi *Exchange) GetAccountId() string {
 	return huobi.accountId
 }
 
-func (ex *Exchange) OpenOrders(symbol string) *OrderReturn {
+// 查询现货账户的挂单, symbol为空时查询所有交易对, 最多返回500笔
+func (ex *Exchange) OpenOrders(symbol string) (*OrderReturn, error) {
 	params := make(map[string]string)
 	params["account-id"] = ex.accountId
-	params["symbol"] = symbol
+	if symbol != "" {
+		params["symbol"] = symbol
+	}
 	params["size"] = "500"
 
 	strRequest := "/v1/order/openOrders"
-	str := ex.ApiKeyGet(make(map[string]string), strRequest)
+	str, err := ex.ApiKeyGet(params, strRequest)
+	if err != nil {
+		return nil, err
+	}
 
 	orderReturn := &OrderReturn{}
 
-	err := json.Unmarshal([]byte(str), orderReturn)
+	err = json.Unmarshal([]byte(str), orderReturn)
 	if err != nil {
-		log.Println(str, err)
+		return nil, err
 	}
-	return orderReturn
+	return orderReturn, nil
 }
 
-func (ex *Exchange) GetOrder(orderId string) *Order {
+func (ex *Exchange) GetOrder(orderId string) (*Order, error) {
 	params := make(map[string]string)
 
 	strRequest := "/v1/order/orders/" + cast.ToString(orderId)
-	str := ex.ApiKeyGet(params, strRequest)
-	//log.Println(str)
+	str, err := ex.ApiKeyGet(params, strRequest)
+	if err != nil {
+		return nil, err
+	}
 
 	orderReturnSingle := &OrderReturnSingle{}
-	err := json.Unmarshal([]byte(str), orderReturnSingle)
+	err = json.Unmarshal([]byte(str), orderReturnSingle)
 	if err != nil {
-		log.Println(str, err)
-		return nil
+		return nil, err
 	}
-	return &orderReturnSingle.Data
+	return &orderReturnSingle.Data, nil
 }
 
-func (ex *Exchange) CancelOrder(orderId string) string {
+// 按用户自编订单号查询订单, 订单不存在时返回错误码为ErrCodeOrderNotFound的APIError
+func (ex *Exchange) GetClientOrder(clientOrderId string) (*Order, error) {
+	params := make(map[string]string)
+	params["clientOrderId"] = clientOrderId
+
+	strRequest := "/v1/order/orders/getClientOrder"
+	str, err := ex.ApiKeyGet(params, strRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	orderReturnSingle := &OrderReturnSingle{}
+	err = json.Unmarshal([]byte(str), orderReturnSingle)
+	if err != nil {
+		return nil, err
+	}
+	return &orderReturnSingle.Data, nil
+}
+
+func (ex *Exchange) CancelOrder(orderId string) (string, error) {
 	params := make(map[string]string)
 
 	strRequest := "/v1/order/orders/" + orderId + "/submitcancel"
-	str := ex.ApiKeyPost(params, strRequest)
+	str, err := ex.ApiKeyPost(params, strRequest)
+	if err != nil {
+		return "", err
+	}
 	id := gjson.Get(str, "data").String()
-	return id
+	return id, nil
 }
 
-func (ex *Exchange) EtpRedemption(symbol, usdt string, amount float64) string {
+func (ex *Exchange) EtpRedemption(symbol, usdt string, amount decimal.Decimal) (string, error) {
 	mapParams := make(map[string]string)
 	mapParams["etpName"] = symbol
 	mapParams["currency"] = usdt
-	mapParams["amount"] = cast.ToString(amount)
+	mapParams["amount"] = amount.String()
 	strRequestUrl := "/v2/etp/redemption"
-	jsonMarketDetailReturn := ex.ApiKeyPost(mapParams, strRequestUrl)
-	return jsonMarketDetailReturn
+	return ex.ApiKeyPost(mapParams, strRequestUrl)
 }
 
-func (ex *Exchange) getEtpTransaction(id string) string {
+func (ex *Exchange) getEtpTransaction(id string) (string, error) {
 	mapParams := make(map[string]string)
 	mapParams["transactId"] = id
 	strRequestUrl := "/v2/etp/transaction"
-	jsonMarketDetailReturn := ex.ApiKeyGet(mapParams, strRequestUrl)
-	return jsonMarketDetailReturn
+	return ex.ApiKeyGet(mapParams, strRequestUrl)
 }
 
-func (ex *Exchange) GetAggregateBalance() *Aggregate {
+func (ex *Exchange) GetAggregateBalance() (*Aggregate, error) {
 	mapParams := make(map[string]string)
 	strRequestUrl := "/v1/subuser/aggregate-balance"
-	resp := ex.ApiKeyGet(mapParams, strRequestUrl)
-	//log.Println(resp)
-	aggregate := &Aggregate{}
-	err := json.Unmarshal([]byte(resp), aggregate)
+	resp, err := ex.ApiKeyGet(mapParams, strRequestUrl)
 	if err != nil {
-		log.Println(err, resp)
-		return nil
+		return nil, err
 	}
-	if gjson.Get(resp, "status").String() == "error" {
-		log.Println(resp)
+	aggregate := &Aggregate{}
+	err = json.Unmarshal([]byte(resp), aggregate)
+	if err != nil {
+		return nil, err
 	}
-	return aggregate
+	return aggregate, nil
 }
 
-func (ex *Exchange) GetContractPositionInfo() *ContractAggregate {
+func (ex *Exchange) GetContractPositionInfo() (*ContractAggregate, error) {
 	mapParam := make(map[string]string)
 	strUrl := "/api/v1/contract_sub_account_list"
-	resp := ex.ContractKeyPost(mapParam, strUrl)
+	resp, err := ex.ContractKeyPost(mapParam, strUrl)
+	if err != nil {
+		return nil, err
+	}
 	agg := &ContractAggregate{}
-	err := json.Unmarshal([]byte(resp), agg)
+	err = json.Unmarshal([]byte(resp), agg)
 	if err != nil {
-		log.Println(err)
-		return nil
+		return nil, err
 	}
-	return agg
+	return agg, nil
 }
 
-func (ex *Exchange) GetSwapPositionInfo() *ContractAggregate {
+func (ex *Exchange) GetSwapPositionInfo() (*ContractAggregate, error) {
 	mapParam := make(map[string]string)
 	strUrl := "/swap-api/v1/swap_sub_account_list"
-	resp := ex.ContractKeyPost(mapParam, strUrl)
+	resp, err := ex.ContractKeyPost(mapParam, strUrl)
+	if err != nil {
+		return nil, err
+	}
 	agg := &ContractAggregate{}
-	err := json.Unmarshal([]byte(resp), agg)
+	err = json.Unmarshal([]byte(resp), agg)
 	if err != nil {
-		log.Println(err)
-		return nil
+		return nil, err
 	}
-	return agg
+	return agg, nil
 }
 
-func (ex *Exchange) GetLinearSwapPositionInfo() *ContractAggregate {
+func (ex *Exchange) GetLinearSwapPositionInfo() (*ContractAggregate, error) {
 	mapParam := make(map[string]string)
 	strUrl := "/linear-swap-api/v1/swap_sub_account_list"
-	resp := ex.ContractKeyPost(mapParam, strUrl)
+	resp, err := ex.ContractKeyPost(mapParam, strUrl)
+	if err != nil {
+		return nil, err
+	}
 	agg := &ContractAggregate{}
-	err := json.Unmarshal([]byte(resp), agg)
+	err = json.Unmarshal([]byte(resp), agg)
 	if err != nil {
-		log.Println(err)
-		return nil
+		return nil, err
 	}
-	return agg
+	return agg, nil
 }
 
-func (ex *Exchange) GetLinearSwapCrossPositionInfo() *ContractAggregate {
+func (ex *Exchange) GetLinearSwapCrossPositionInfo() (*ContractAggregate, error) {
 	mapParam := make(map[string]string)
 	strUrl := "/linear-swap-api/v1/swap_cross_sub_account_list"
-	resp := ex.ContractKeyPost(mapParam, strUrl)
+	resp, err := ex.ContractKeyPost(mapParam, strUrl)
+	if err != nil {
+		return nil, err
+	}
 	agg := &ContractAggregate{}
-	err := json.Unmarshal([]byte(resp), agg)
+	err = json.Unmarshal([]byte(resp), agg)
 	if err != nil {
-		log.Println(err, resp)
-		return nil
+		return nil, err
 	}
-	return agg
+	return agg, nil
 }
 
-func (ex *Exchange) GetOptionPositionInfo() *ContractAggregate {
+func (ex *Exchange) GetOptionPositionInfo() (*ContractAggregate, error) {
 	mapParam := make(map[string]string)
 	strUrl := "/option-api/v1/option_sub_account_list"
-	resp := ex.ContractKeyPost(mapParam, strUrl)
-	log.Println(resp)
+	resp, err := ex.ContractKeyPost(mapParam, strUrl)
+	if err != nil {
+		return nil, err
+	}
 	agg := &ContractAggregate{}
-	err := json.Unmarshal([]byte(resp), agg)
+	err = json.Unmarshal([]byte(resp), agg)
 	if err != nil {
-		log.Println(err)
-		return nil
+		return nil, err
 	}
-	return agg
+	return agg, nil
 }
 
 func (ex *Exchange) GetAssetValuation(accountType string, valuationCurrency string) (string, error) {
@@ -300,42 +362,26 @@ func (ex *Exchange) GetAssetValuation(accountType string, valuationCurrency stri
 	mapParams["accountType"] = accountType
 	mapParams["valuationCurrency"] = valuationCurrency
 	strRequestUrl := "/v2/account/asset-valuation"
-	resp := ex.ApiKeyGet(mapParams, strRequestUrl)
-	if gjson.Get(resp, "code").Int() == 200 {
-		return resp, nil
-	}
-	return resp, errors.New(resp)
+	return ex.ApiKeyGet(mapParams, strRequestUrl)
 }
 
 func (ex *Exchange) GetContractBalanceValuation(valuation_asset string) (string, error) {
 	mapParams := make(map[string]string)
 	mapParams["valuation_asset"] = valuation_asset
 	strUrl := "/api/v1/contract_balance_valuation"
-	resp := ex.ContractKeyPost(mapParams, strUrl)
-	if gjson.Get(resp, "status").String() == "ok" {
-		return resp, nil
-	}
-	return resp, errors.New(resp)
+	return ex.ContractKeyPost(mapParams, strUrl)
 }
 
 func (ex *Exchange) GetSwapBalanceValuation(valuation_asset string) (string, error) {
 	mapParams := make(map[string]string)
 	mapParams["valuation_asset"] = valuation_asset
 	strUrl := "/swap-api/v1/swap_balance_valuation"
-	resp := ex.ContractKeyPost(mapParams, strUrl)
-	if gjson.Get(resp, "status").String() == "ok" {
-		return resp, nil
-	}
-	return resp, errors.New(resp)
+	return ex.ContractKeyPost(mapParams, strUrl)
 }
 
 func (ex *Exchange) GetLinearSwapBalanceValuation(valuation_asset string) (string, error) {
 	mapParams := make(map[string]string)
 	mapParams["valuation_asset"] = valuation_asset
 	strUrl := "/linear-swap-api/v1/swap_balance_valuation"
-	resp := ex.ContractKeyPost(mapParams, strUrl)
-	if gjson.Get(resp, "status").String() == "ok" {
-		return resp, nil
-	}
-	return resp, errors.New(resp)
+	return ex.ContractKeyPost(mapParams, strUrl)
 }
\ No newline at end of file
@@ -0,0 +1,720 @@
+package huobitest
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/monkeybang/huobi"
+	"github.com/shopspring/decimal"
+	"github.com/spf13/cast"
+)
+
+const (
+	DefaultAccountID = 1001 // 默认现货账户ID
+	DefaultUserID    = 2001 // 默认用户ID
+	MaxBatchOrders   = 10   // 批量下单每次最多的订单数
+	MaxBatchCancel   = 50   // 按订单ID批量撤单每次最多的订单数
+	MaxMatchResults  = 500  // 查询成交明细每页最多的数量
+)
+
+// 默认的模拟成交手续费率, 0.2%
+var DefaultFeeRate = decimal.New(2, -3)
+
+// 订单状态
+const (
+	OrderStateSubmitted     = huobi.OrderStateSubmitted
+	OrderStatePartialFilled = huobi.OrderStatePartialFilled
+	OrderStateFilled        = huobi.OrderStateFilled
+	OrderStateCanceled      = huobi.OrderStateCanceled
+)
+
+// 模拟服务保存的订单, 字段名与/v1/order/orders/{order-id}的响应一致
+type Order struct {
+	ID               int64              `json:"id"`
+	Symbol           string             `json:"symbol"`
+	AccountID        int64              `json:"account-id"`
+	ClientOrderID    string             `json:"client-order-id,omitempty"`
+	Amount           decimal.Decimal    `json:"amount"`
+	Price            decimal.Decimal    `json:"price"`
+	CreatedAt        int64              `json:"created-at"`
+	CanceledAt       int64              `json:"canceled-at"`
+	FinishedAt       int64              `json:"finished-at"`
+	Type             huobi.OrderType    `json:"type"`
+	FilledAmount     decimal.Decimal    `json:"field-amount"`
+	FilledCashAmount decimal.Decimal    `json:"field-cash-amount"`
+	FilledFees       decimal.Decimal    `json:"field-fees"`
+	Source           string             `json:"source"`
+	State            string             `json:"state"`
+	StopPrice        decimal.Decimal    `json:"stop-price"`
+	Operator         huobi.StopOperator `json:"operator,omitempty"`
+}
+
+func (o *Order) open() bool {
+	return o.State == OrderStateSubmitted || o.State == OrderStatePartialFilled
+}
+
+// 默认的交易对: btcusdt, ethusdt, ethbtc
+func DefaultSymbols() []*huobi.SymbolsData {
+	return []*huobi.SymbolsData{
+		{Symbol: "btcusdt", State: "online", ApiTrading: "enabled", BaseCurrency: "btc", QuoteCurrency: "usdt", PricePrecision: 2, AmountPrecision: 6, ValuePrecision: 8, SymbolPartition: "main", LimitOrderMinOrderAmt: decimal.New(1, -4), LimitOrderMaxOrderAmt: decimal.New(1000, 0)},
+		{Symbol: "ethusdt", State: "online", ApiTrading: "enabled", BaseCurrency: "eth", QuoteCurrency: "usdt", PricePrecision: 2, AmountPrecision: 4, ValuePrecision: 8, SymbolPartition: "main", LimitOrderMinOrderAmt: decimal.New(1, -3), LimitOrderMaxOrderAmt: decimal.New(10000, 0)},
+		{Symbol: "ethbtc", State: "online", ApiTrading: "enabled", BaseCurrency: "eth", QuoteCurrency: "btc", PricePrecision: 6, AmountPrecision: 4, ValuePrecision: 8, SymbolPartition: "main", LimitOrderMinOrderAmt: decimal.New(1, -3), LimitOrderMaxOrderAmt: decimal.New(10000, 0)},
+	}
+}
+
+// 替换交易对列表
+func (s *Server) SetSymbols(symbols []*huobi.SymbolsData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.symbols = symbols
+}
+
+// 替换账户列表
+func (s *Server) SetAccounts(accounts []huobi.AccountsData) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.accounts = accounts
+}
+
+// 设置账户中一个币种的可用和冻结余额
+func (s *Server) SetBalance(accountID int64, currency string, trade, frozen decimal.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	list := s.balances[accountID][:0:0]
+	for _, subAccount := range s.balances[accountID] {
+		if subAccount.Currency != currency {
+			list = append(list, subAccount)
+		}
+	}
+	list = append(list,
+		huobi.SubAccount{Currency: currency, Type: "trade", Balance: trade},
+		huobi.SubAccount{Currency: currency, Type: "frozen", Balance: frozen})
+	s.balances[accountID] = list
+}
+
+// 所有订单的副本, 按下单顺序排列
+func (s *Server) Orders() []Order {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	orders := make([]Order, 0, len(s.orders))
+	for _, order := range s.orders {
+		orders = append(orders, *order)
+	}
+	return orders
+}
+
+// 设置模拟成交的手续费率, 买单以基础币种收取, 卖单以计价币种收取
+func (s *Server) SetFeeRate(rate decimal.Decimal) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.feeRate = rate
+}
+
+// 已生成的成交明细
+func (s *Server) MatchResults() []huobi.MatchResult {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]huobi.MatchResult(nil), s.matchResults...)
+}
+
+// 模拟成交, filledAmount为累计成交量, 全部成交时订单变为filled
+// 新增的成交量按订单价格生成一笔成交明细, limit-maker订单为maker, 其他为taker
+func (s *Server) FillOrder(orderID int64, filledAmount decimal.Decimal) bool {
+	return s.FillOrderAt(orderID, filledAmount, time.Now())
+}
+
+// 同FillOrder, 成交明细的成交时间为at, 用于模拟历史成交
+func (s *Server) FillOrderAt(orderID int64, filledAmount decimal.Decimal, at time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	order := s.findOrder(strconv.FormatInt(orderID, 10))
+	if order == nil || !order.open() {
+		return false
+	}
+	if filledAmount.GreaterThanOrEqual(order.Amount) {
+		filledAmount = order.Amount
+		order.State = OrderStateFilled
+		order.FinishedAt = s.finishTime()
+	} else {
+		order.State = OrderStatePartialFilled
+	}
+	if amount := filledAmount.Sub(order.FilledAmount); amount.IsPositive() {
+		result := s.matchResult(order, amount, at)
+		order.FilledFees = order.FilledFees.Add(result.FilledFees)
+		s.matchResults = append(s.matchResults, result)
+	}
+	order.FilledAmount = filledAmount
+	order.FilledCashAmount = filledAmount.Mul(order.Price)
+	return true
+}
+
+func (s *Server) matchResult(order *Order, amount decimal.Decimal, at time.Time) huobi.MatchResult {
+	s.lastMatch++
+	result := huobi.MatchResult{
+		ID:             s.lastMatch,
+		Symbol:         order.Symbol,
+		OrderID:        order.ID,
+		MatchID:        s.lastMatch,
+		TradeID:        s.lastMatch,
+		Type:           order.Type,
+		Source:         order.Source,
+		Price:          order.Price,
+		FilledAmount:   amount,
+		FeeDeductState: "done",
+		Role:           huobi.RoleTaker,
+		CreatedAt:      millis(at),
+	}
+	if order.Type == huobi.OrderTypeBuyLimitMaker || order.Type == huobi.OrderTypeSellLimitMaker {
+		result.Role = huobi.RoleMaker
+	}
+	symbol := s.findSymbol(order.Symbol)
+	if order.Type.IsBuy() {
+		result.FilledFees = amount.Mul(s.feeRate)
+		if symbol != nil {
+			result.FeeCurrency = symbol.BaseCurrency
+		}
+	} else {
+		result.FilledFees = amount.Mul(order.Price).Mul(s.feeRate)
+		if symbol != nil {
+			result.FeeCurrency = symbol.QuoteCurrency
+		}
+	}
+	return result
+}
+
+// 按订单ID查找订单, 调用方需持有锁
+func (s *Server) findOrder(orderID string) *Order {
+	for _, order := range s.orders {
+		if strconv.FormatInt(order.ID, 10) == orderID {
+			return order
+		}
+	}
+	return nil
+}
+
+func (s *Server) findClientOrder(clientOrderID string) *Order {
+	for _, order := range s.orders {
+		if clientOrderID != "" && order.ClientOrderID == clientOrderID {
+			return order
+		}
+	}
+	return nil
+}
+
+func (s *Server) findSymbol(symbol string) *huobi.SymbolsData {
+	for _, symbolsData := range s.symbols {
+		if symbolsData.Name() == symbol {
+			return symbolsData
+		}
+	}
+	return nil
+}
+
+func (s *Server) findAccount(accountID string) bool {
+	for _, account := range s.accounts {
+		if strconv.FormatInt(account.ID, 10) == accountID {
+			return true
+		}
+	}
+	return false
+}
+
+func millis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// 订单的结束时间, 保证每笔订单不同, 使按时间翻页时不会重复或遗漏, 调用方需持有锁
+func (s *Server) finishTime() int64 {
+	now := millis(time.Now())
+	if now <= s.lastFinish {
+		now = s.lastFinish + 1
+	}
+	s.lastFinish = now
+	return now
+}
+
+// 合约接口的路径
+func contract(path string) bool {
+	return strings.HasPrefix(path, "/api/") || strings.HasPrefix(path, "/swap-api/") || strings.HasPrefix(path, "/linear-swap-api/") || strings.HasPrefix(path, "/option-api/")
+}
+
+// 内置接口
+func (s *Server) route(w http.ResponseWriter, request *Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	path := request.Path
+	switch {
+	case request.Method == http.MethodGet && path == "/v1/common/symbols":
+		writeData(w, s.symbols)
+	case request.Method == http.MethodGet && path == "/v2/settings/common/symbols":
+		writeJSON(w, map[string]interface{}{"status": "ok", "ts": strconv.FormatInt(millis(time.Now()), 10), "full": 1, "data": s.symbolsV2()})
+	case request.Method == http.MethodGet && path == "/v1/common/currencys":
+		writeData(w, s.currencys())
+	case request.Method == http.MethodGet && path == "/v1/common/timestamp":
+		writeData(w, millis(time.Now()))
+	case request.Method == http.MethodGet && path == "/v1/account/accounts":
+		writeData(w, s.accounts)
+	case request.Method == http.MethodGet && strings.HasPrefix(path, "/v1/account/accounts/") && strings.HasSuffix(path, "/balance"):
+		s.balance(w, request)
+	case request.Method == http.MethodPost && path == "/v1/order/orders/place":
+		s.place(w, request)
+	case request.Method == http.MethodPost && path == "/v1/order/batch-orders":
+		s.batchPlace(w, request)
+	case request.Method == http.MethodGet && path == "/v1/order/orders/getClientOrder":
+		s.writeOrder(w, request, s.findClientOrder(request.Query.Get("clientOrderId")))
+	case request.Method == http.MethodGet && path == "/v1/order/orders":
+		s.searchOrders(w, request)
+	case request.Method == http.MethodGet && path == "/v1/order/history":
+		s.orderHistory(w, request)
+	case request.Method == http.MethodGet && path == "/v1/order/openOrders":
+		s.openOrders(w, request)
+	case request.Method == http.MethodPost && path == "/v1/order/orders/batchCancelOpenOrders":
+		s.batchCancelOpenOrders(w, request)
+	case request.Method == http.MethodPost && path == "/v1/order/orders/batchcancel":
+		s.batchCancel(w, request)
+	case request.Method == http.MethodPost && strings.HasPrefix(path, "/v1/order/orders/") && strings.HasSuffix(path, "/submitcancel"):
+		s.submitCancel(w, request, strings.TrimSuffix(strings.TrimPrefix(path, "/v1/order/orders/"), "/submitcancel"))
+	case request.Method == http.MethodGet && path == "/v1/order/matchresults":
+		s.searchMatchResults(w, request)
+	case request.Method == http.MethodGet && strings.HasPrefix(path, "/v1/order/orders/") && strings.HasSuffix(path, "/matchresults"):
+		s.orderMatchResults(w, request, strings.TrimSuffix(strings.TrimPrefix(path, "/v1/order/orders/"), "/matchresults"))
+	case request.Method == http.MethodGet && strings.HasPrefix(path, "/v1/order/orders/"):
+		s.writeOrder(w, request, s.findOrder(strings.TrimPrefix(path, "/v1/order/orders/")))
+	case contract(path):
+		// 合约接口默认返回空列表, 需要数据时用SetResponse指定
+		writeJSON(w, map[string]interface{}{"status": "ok", "data": []interface{}{}, "ts": millis(time.Now())})
+	default:
+		writeError(w, path, http.StatusNotFound, "not-found", "no such api: "+request.Method+" "+path)
+	}
+}
+
+// 由交易对列表生成v2格式的交易对
+func (s *Server) symbolsV2() []*huobi.SymbolV2 {
+	symbols := make([]*huobi.SymbolV2, 0, len(s.symbols))
+	for _, symbolsData := range s.symbols {
+		symbols = append(symbols, &huobi.SymbolV2{
+			Symbol:               symbolsData.Name(),
+			DisplayName:          strings.ToUpper(symbolsData.BaseCurrency + "/" + symbolsData.QuoteCurrency),
+			BaseCurrency:         symbolsData.BaseCurrency,
+			QuoteCurrency:        symbolsData.QuoteCurrency,
+			State:                symbolsData.State,
+			TradeEnabled:         symbolsData.IsTradable(),
+			SymbolPartition:      symbolsData.SymbolPartition,
+			TradeTotalPrecision:  symbolsData.ValuePrecision,
+			TradeAmountPrecision: symbolsData.AmountPrecision,
+			TradePricePrecision:  symbolsData.PricePrecision,
+			Tags:                 symbolsData.Tags,
+		})
+	}
+	return symbols
+}
+
+func (s *Server) currencys() []string {
+	seen := make(map[string]bool)
+	currencys := make([]string, 0)
+	for _, symbolsData := range s.symbols {
+		for _, currency := range []string{symbolsData.BaseCurrency, symbolsData.QuoteCurrency} {
+			if !seen[currency] {
+				seen[currency] = true
+				currencys = append(currencys, currency)
+			}
+		}
+	}
+	sort.Strings(currencys)
+	return currencys
+}
+
+func (s *Server) balance(w http.ResponseWriter, request *Request) {
+	accountID := strings.TrimSuffix(strings.TrimPrefix(request.Path, "/v1/account/accounts/"), "/balance")
+	for _, account := range s.accounts {
+		if strconv.FormatInt(account.ID, 10) == accountID {
+			list := s.balances[account.ID]
+			if list == nil {
+				list = []huobi.SubAccount{}
+			}
+			writeData(w, huobi.Balance{ID: account.ID, State: account.State, Type: account.Type, List: list, UserID: account.UserID})
+			return
+		}
+	}
+	writeError(w, request.Path, http.StatusOK, "account-get-accounts-inexistent-error", "account for id `"+accountID+"` and user id does not exist")
+}
+
+func (s *Server) place(w http.ResponseWriter, request *Request) {
+	order, code, message := s.placeOrder(request.Body)
+	if order == nil {
+		writeError(w, request.Path, http.StatusOK, code, message)
+		return
+	}
+	writeData(w, strconv.FormatInt(order.ID, 10))
+}
+
+// 批量下单, 每笔订单单独返回order-id或err-code
+func (s *Server) batchPlace(w http.ResponseWriter, request *Request) {
+	if request.Items == nil {
+		writeError(w, request.Path, http.StatusOK, huobi.ErrCodeInvalidParameter, "request body must be an array of orders")
+		return
+	}
+	if len(request.Items) > MaxBatchOrders {
+		writeError(w, request.Path, http.StatusOK, huobi.ErrCodeInvalidParameter, "too many orders, max "+strconv.Itoa(MaxBatchOrders))
+		return
+	}
+	results := make([]map[string]interface{}, 0, len(request.Items))
+	for _, params := range request.Items {
+		result := map[string]interface{}{"client-order-id": params["client-order-id"]}
+		if order, code, message := s.placeOrder(params); order != nil {
+			result["order-id"] = order.ID
+		} else {
+			result["err-code"] = code
+			result["err-msg"] = message
+		}
+		results = append(results, result)
+	}
+	writeData(w, results)
+}
+
+// 按下单参数创建订单, 失败时返回错误码和错误信息
+func (s *Server) placeOrder(params map[string]string) (*Order, string, string) {
+	if !s.findAccount(params["account-id"]) {
+		return nil, "account-get-accounts-inexistent-error", "account for id `" + params["account-id"] + "` and user id does not exist"
+	}
+	if s.findSymbol(params["symbol"]) == nil {
+		return nil, "base-symbol-error", "The symbol is invalid."
+	}
+	orderType := huobi.OrderType(params["type"])
+	if !orderType.IsValid() {
+		return nil, huobi.ErrCodeInvalidParameter, "invalid type: " + params["type"]
+	}
+	amount, err := decimal.NewFromString(params["amount"])
+	if err != nil || !amount.IsPositive() {
+		return nil, "order-value-min-error", "order amount must be positive"
+	}
+	price := decimal.Zero
+	if params["price"] != "" {
+		if price, err = decimal.NewFromString(params["price"]); err != nil {
+			return nil, huobi.ErrCodeInvalidParameter, "invalid price: " + params["price"]
+		}
+	}
+	stopPrice := decimal.Zero
+	if orderType.IsStop() {
+		operator := huobi.StopOperator(params["operator"])
+		if stopPrice, err = decimal.NewFromString(params["stop-price"]); err != nil || (operator != huobi.OperatorGTE && operator != huobi.OperatorLTE) {
+			return nil, huobi.ErrCodeInvalidParameter, "stop-price and operator required for " + params["type"]
+		}
+	}
+	if s.findClientOrder(params["client-order-id"]) != nil {
+		return nil, "client-order-id-duplicate", "client-order-id already exists"
+	}
+
+	s.lastOrder++
+	order := &Order{
+		ID:            s.lastOrder,
+		Symbol:        params["symbol"],
+		AccountID:     cast.ToInt64(params["account-id"]),
+		ClientOrderID: params["client-order-id"],
+		Amount:        amount,
+		Price:         price,
+		CreatedAt:     millis(time.Now()),
+		Type:          orderType,
+		Source:        params["source"],
+		State:         OrderStateSubmitted,
+		StopPrice:     stopPrice,
+		Operator:      huobi.StopOperator(params["operator"]),
+	}
+	s.orders = append(s.orders, order)
+	return order, "", ""
+}
+
+func (s *Server) writeOrder(w http.ResponseWriter, request *Request, order *Order) {
+	if order == nil {
+		writeError(w, request.Path, http.StatusOK, huobi.ErrCodeOrderNotFound, "record invalid")
+		return
+	}
+	writeData(w, order)
+}
+
+func (s *Server) openOrders(w http.ResponseWriter, request *Request) {
+	accountID := request.Query.Get("account-id")
+	symbol := request.Query.Get("symbol")
+	orders := make([]*Order, 0)
+	for _, order := range s.orders {
+		if !order.open() {
+			continue
+		}
+		if accountID != "" && strconv.FormatInt(order.AccountID, 10) != accountID {
+			continue
+		}
+		if symbol != "" && order.Symbol != symbol {
+			continue
+		}
+		orders = append(orders, order)
+	}
+	writeData(w, orders)
+}
+
+// 按symbol, types, states, start-time, end-time查询订单, 按from和direct翻页
+// direct为next时按订单ID从大到小返回小于from的订单, 为prev时从小到大返回大于from的订单
+func (s *Server) searchOrders(w http.ResponseWriter, request *Request) {
+	query := request.Query
+	if query.Get("symbol") == "" || query.Get("states") == "" {
+		writeError(w, request.Path, http.StatusOK, huobi.ErrCodeInvalidParameter, "symbol and states required")
+		return
+	}
+	size := cast.ToInt(query.Get("size"))
+	if size <= 0 || size > 100 {
+		size = 100
+	}
+	from := cast.ToInt64(query.Get("from"))
+	prev := query.Get("direct") == string(huobi.PagePrev)
+	startTime := cast.ToInt64(query.Get("start-time"))
+	endTime := cast.ToInt64(query.Get("end-time"))
+
+	orders := make([]*Order, 0)
+	for i := range s.orders {
+		order := s.orders[len(s.orders)-1-i]
+		if prev {
+			order = s.orders[i]
+		}
+		if len(orders) == size {
+			break
+		}
+		if from != 0 && ((prev && order.ID <= from) || (!prev && order.ID >= from)) {
+			continue
+		}
+		if order.Symbol != query.Get("symbol") || !inList(query.Get("states"), order.State) {
+			continue
+		}
+		if types := query.Get("types"); types != "" && !inList(types, string(order.Type)) {
+			continue
+		}
+		if (startTime != 0 && order.CreatedAt < startTime) || (endTime != 0 && order.CreatedAt > endTime) {
+			continue
+		}
+		orders = append(orders, order)
+	}
+	writeData(w, orders)
+}
+
+// 按结束时间查询已结束的订单, 还有更多订单时next-time为下一页的开始时间(prev)或结束时间(next)
+func (s *Server) orderHistory(w http.ResponseWriter, request *Request) {
+	query := request.Query
+	size := cast.ToInt(query.Get("size"))
+	if size <= 0 {
+		size = 100
+	}
+	prev := query.Get("direct") == string(huobi.PagePrev)
+	startTime := cast.ToInt64(query.Get("start-time"))
+	endTime := cast.ToInt64(query.Get("end-time"))
+
+	var finished []*Order
+	for _, order := range s.orders {
+		if order.open() {
+			continue
+		}
+		if symbol := query.Get("symbol"); symbol != "" && order.Symbol != symbol {
+			continue
+		}
+		if (startTime != 0 && order.FinishedAt < startTime) || (endTime != 0 && order.FinishedAt > endTime) {
+			continue
+		}
+		finished = append(finished, order)
+	}
+	sort.SliceStable(finished, func(i, j int) bool {
+		if prev {
+			return finished[i].FinishedAt < finished[j].FinishedAt
+		}
+		return finished[i].FinishedAt > finished[j].FinishedAt
+	})
+
+	orders := make([]*Order, 0)
+	var nextTime int64
+	for _, order := range finished {
+		if len(orders) == size {
+			nextTime = order.FinishedAt
+			break
+		}
+		orders = append(orders, order)
+	}
+	writeJSON(w, map[string]interface{}{"status": "ok", "data": orders, "next-time": nextTime})
+}
+
+func (s *Server) orderMatchResults(w http.ResponseWriter, request *Request, orderID string) {
+	order := s.findOrder(orderID)
+	if order == nil {
+		writeError(w, request.Path, http.StatusOK, huobi.ErrCodeOrderNotFound, "record invalid")
+		return
+	}
+	results := make([]huobi.MatchResult, 0)
+	for _, result := range s.matchResults {
+		if result.OrderID == order.ID {
+			results = append(results, result)
+		}
+	}
+	writeData(w, results)
+}
+
+// 按symbol, types, start-time, end-time查询成交明细, 按from和direct翻页
+// 未指定时间时查询最近48小时, 时间范围不能超过48小时
+func (s *Server) searchMatchResults(w http.ResponseWriter, request *Request) {
+	query := request.Query
+	if query.Get("symbol") == "" {
+		writeError(w, request.Path, http.StatusOK, huobi.ErrCodeInvalidParameter, "symbol required")
+		return
+	}
+	window := int64(huobi.MatchResultsWindow / time.Millisecond)
+	endTime := cast.ToInt64(query.Get("end-time"))
+	if endTime == 0 {
+		endTime = millis(time.Now())
+	}
+	startTime := cast.ToInt64(query.Get("start-time"))
+	if startTime == 0 {
+		startTime = endTime - window
+	}
+	if endTime < startTime || endTime-startTime > window {
+		writeError(w, request.Path, http.StatusOK, huobi.ErrCodeInvalidParameter, "invalid time range, max 48 hours")
+		return
+	}
+	size := cast.ToInt(query.Get("size"))
+	if size <= 0 {
+		size = 100
+	}
+	if size > MaxMatchResults {
+		size = MaxMatchResults
+	}
+	from := cast.ToInt64(query.Get("from"))
+	prev := query.Get("direct") == string(huobi.PagePrev)
+
+	results := make([]huobi.MatchResult, 0)
+	for i := range s.matchResults {
+		result := s.matchResults[len(s.matchResults)-1-i]
+		if prev {
+			result = s.matchResults[i]
+		}
+		if len(results) == size {
+			break
+		}
+		if from != 0 && ((prev && result.ID <= from) || (!prev && result.ID >= from)) {
+			continue
+		}
+		if result.Symbol != query.Get("symbol") || result.CreatedAt < startTime || result.CreatedAt > endTime {
+			continue
+		}
+		if types := query.Get("types"); types != "" && !inList(types, string(result.Type)) {
+			continue
+		}
+		results = append(results, result)
+	}
+	writeData(w, results)
+}
+
+func (s *Server) submitCancel(w http.ResponseWriter, request *Request, orderID string) {
+	order := s.findOrder(orderID)
+	if order == nil {
+		writeError(w, request.Path, http.StatusOK, huobi.ErrCodeOrderNotFound, "record invalid")
+		return
+	}
+	if !order.open() {
+		writeError(w, request.Path, http.StatusOK, "order-orderstate-error", "Incorrect order state")
+		return
+	}
+	order.State = OrderStateCanceled
+	order.CanceledAt = s.finishTime()
+	order.FinishedAt = order.CanceledAt
+	writeData(w, orderID)
+}
+
+// 按条件撤销挂单, 每次最多撤销size笔, 还有符合条件的挂单时next-id为下一笔的订单ID
+func (s *Server) batchCancelOpenOrders(w http.ResponseWriter, request *Request) {
+	params := request.Body
+	size := cast.ToInt(params["size"])
+	if size <= 0 || size > 100 {
+		size = 100
+	}
+	count := 0
+	nextID := int64(-1)
+	for _, order := range s.orders {
+		if !order.open() || !matchFilter(order, params) {
+			continue
+		}
+		if count == size {
+			nextID = order.ID
+			break
+		}
+		order.State = OrderStateCanceled
+		order.CanceledAt = s.finishTime()
+		order.FinishedAt = order.CanceledAt
+		count++
+	}
+	writeData(w, map[string]int64{"success-count": int64(count), "failed-count": 0, "next-id": nextID})
+}
+
+// 订单是否符合account-id, symbol, side, types条件, symbol和types可以是逗号分隔的列表
+func matchFilter(order *Order, params map[string]string) bool {
+	if accountID := params["account-id"]; accountID != "" && strconv.FormatInt(order.AccountID, 10) != accountID {
+		return false
+	}
+	if symbol := params["symbol"]; symbol != "" && !inList(symbol, order.Symbol) {
+		return false
+	}
+	if side := params["side"]; side != "" && string(order.Type.Side()) != side {
+		return false
+	}
+	if types := params["types"]; types != "" && !inList(types, string(order.Type)) {
+		return false
+	}
+	return true
+}
+
+func inList(list, value string) bool {
+	for _, item := range strings.Split(list, ",") {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// 按order-ids或client-order-ids批量撤单
+func (s *Server) batchCancel(w http.ResponseWriter, request *Request) {
+	var body struct {
+		OrderIDs       []string `json:"order-ids"`
+		ClientOrderIDs []string `json:"client-order-ids"`
+	}
+	if err := json.Unmarshal(request.raw, &body); err != nil {
+		writeError(w, request.Path, http.StatusOK, huobi.ErrCodeInvalidParameter, "invalid request body: "+err.Error())
+		return
+	}
+	if len(body.OrderIDs)+len(body.ClientOrderIDs) > MaxBatchCancel {
+		writeError(w, request.Path, http.StatusOK, huobi.ErrCodeInvalidParameter, "too many orders, max "+strconv.Itoa(MaxBatchCancel))
+		return
+	}
+
+	success := make([]string, 0)
+	failed := make([]huobi.BatchCancelFailed, 0)
+	cancel := func(order *Order, orderID, clientOrderID string) {
+		switch {
+		case order == nil:
+			failed = append(failed, huobi.BatchCancelFailed{OrderID: orderID, ClientOrderID: clientOrderID, OrderState: -1,
+				ErrCode: "base-not-found", ErrMsg: "the record is not found."})
+		case !order.open():
+			failed = append(failed, huobi.BatchCancelFailed{OrderID: orderID, ClientOrderID: clientOrderID, OrderState: 7,
+				ErrCode: "order-orderstate-error", ErrMsg: "Incorrect order state"})
+		default:
+			order.State = OrderStateCanceled
+			order.CanceledAt = s.finishTime()
+			order.FinishedAt = order.CanceledAt
+			success = append(success, orderID+clientOrderID)
+		}
+	}
+	for _, orderID := range body.OrderIDs {
+		cancel(s.findOrder(orderID), orderID, "")
+	}
+	for _, clientOrderID := range body.ClientOrderIDs {
+		cancel(s.findClientOrder(clientOrderID), "", clientOrderID)
+	}
+	writeData(w, huobi.BatchCancelData{Success: success, Failed: failed})
+}
@@ -0,0 +1,281 @@
+// 基于httptest的本地火币模拟服务, 用于在无网络环境下测试使用huobi包的代码
+//
+// 用法:
+//
+//	server := huobitest.NewServer("ak", "sk")
+//	defer server.Close()
+//	ex, err := huobi.OpenExchange("ak", "sk", server.Options()...)
+//
+// 签名请求按CreateSign校验HmacSHA256签名, 内置交易对、账户、订单和合约接口的默认响应
+// 可以用SetResponse、Handle替换任意接口的响应, 用FailNext模拟错误码, 用SetLatency模拟延迟
+package huobitest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/monkeybang/huobi"
+	"github.com/shopspring/decimal"
+)
+
+// 签名时间戳允许的最大偏差
+const MaxTimestampSkew = 5 * time.Minute
+
+// 服务收到的一个请求
+type Request struct {
+	Method string
+	Path   string
+	Query  url.Values          // URL中的参数, 签名请求包含AccessKeyId、Signature等
+	Body   map[string]string   // POST请求的JSON参数
+	Items  []map[string]string // POST请求体为JSON数组时的各项, 如批量下单
+	Time   time.Time
+
+	raw []byte
+}
+
+// 按顺序返回的错误
+type failure struct {
+	remain     int
+	httpStatus int
+	code       string
+	message    string
+}
+
+// 模拟的火币服务, 同时作为现货和合约地址
+type Server struct {
+	*httptest.Server
+
+	accessKey string
+	secretKey string
+
+	mu         sync.Mutex
+	latency    time.Duration
+	handlers   map[string]http.HandlerFunc
+	failures   map[string]*failure
+	requests   []Request
+	symbols    []*huobi.SymbolsData
+	accounts   []huobi.AccountsData
+	balances   map[int64][]huobi.SubAccount
+	orders     []*Order
+	lastOrder  int64
+	lastFinish int64
+
+	feeRate      decimal.Decimal
+	matchResults []huobi.MatchResult
+	lastMatch    int64
+}
+
+// 启动模拟服务, 只接受用accessKey, secretKey签名的请求, 需调用Close关闭
+func NewServer(accessKey, secretKey string) *Server {
+	s := &Server{
+		accessKey: accessKey,
+		secretKey: secretKey,
+		handlers:  make(map[string]http.HandlerFunc),
+		failures:  make(map[string]*failure),
+		symbols:   DefaultSymbols(),
+		accounts:  []huobi.AccountsData{{ID: DefaultAccountID, Type: "spot", State: "working", UserID: DefaultUserID}},
+		balances:  make(map[int64][]huobi.SubAccount),
+		feeRate:   DefaultFeeRate,
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// 签名使用的主机名
+func (s *Server) Host() string {
+	return s.Listener.Addr().String()
+}
+
+// 让Exchange的现货、行情和合约请求都发往模拟服务
+func (s *Server) Options() []huobi.Option {
+	return []huobi.Option{
+		huobi.WithSpotEndpoint(s.URL, s.Host()),
+		huobi.WithContractEndpoint(s.URL, s.Host()),
+	}
+}
+
+// 每个请求在响应前等待latency, 请求被取消时提前返回
+func (s *Server) SetLatency(latency time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = latency
+}
+
+// 用handler处理method path的请求, 替代内置的处理
+// 签名校验、FailNext和延迟仍然生效
+func (s *Server) Handle(method, path string, handler http.HandlerFunc) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[method+" "+path] = handler
+}
+
+// 对method path的请求固定返回body
+func (s *Server) SetResponse(method, path, body string) {
+	s.Handle(method, path, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, body)
+	})
+}
+
+// 接下来n次method path的请求返回错误, httpStatus为0时按200返回
+// 例: FailNext("POST", "/v1/order/orders/place", 1, 0, huobi.ErrCodeBalanceInsufficient, "balance insufficient")
+func (s *Server) FailNext(method, path string, n int, httpStatus int, code, message string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if httpStatus == 0 {
+		httpStatus = http.StatusOK
+	}
+	s.failures[method+" "+path] = &failure{remain: n, httpStatus: httpStatus, code: code, message: message}
+}
+
+// 已收到的请求
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	requests := make([]Request, len(s.requests))
+	copy(requests, s.requests)
+	return requests
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	request := Request{Method: r.Method, Path: r.URL.Path, Query: r.URL.Query(), Time: time.Now()}
+	if r.Method == http.MethodPost {
+		body, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, r.URL.Path, http.StatusBadRequest, huobi.ErrCodeInvalidParameter, err.Error())
+			return
+		}
+		request.raw = body
+		request.Body, request.Items = parseBody(body)
+	}
+
+	s.mu.Lock()
+	s.requests = append(s.requests, request)
+	latency := s.latency
+	key := r.Method + " " + r.URL.Path
+	handler := s.handlers[key]
+	var fail *failure
+	if f := s.failures[key]; f != nil && f.remain > 0 {
+		f.remain--
+		fail = f
+	}
+	s.mu.Unlock()
+
+	if latency > 0 {
+		select {
+		case <-time.After(latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+
+	if signed(r.URL.Path) {
+		if code, message := s.verify(r); code != "" {
+			writeError(w, r.URL.Path, http.StatusOK, code, message)
+			return
+		}
+	}
+	if fail != nil {
+		writeError(w, r.URL.Path, fail.httpStatus, fail.code, fail.message)
+		return
+	}
+	if handler != nil {
+		handler(w, r)
+		return
+	}
+	s.route(w, &request)
+}
+
+// 行情和公共接口不需要签名
+func signed(path string) bool {
+	return !strings.HasPrefix(path, "/market") && !strings.HasPrefix(path, "/v1/common") && !strings.HasPrefix(path, "/v2/settings")
+}
+
+// 校验签名, 返回错误码和错误信息, 校验通过时返回空字符串
+func (s *Server) verify(r *http.Request) (string, string) {
+	query := r.URL.Query()
+	params := make(map[string]string)
+	for key := range query {
+		params[key] = query.Get(key)
+	}
+	signature := params["Signature"]
+	delete(params, "Signature")
+
+	if params["AccessKeyId"] == "" || signature == "" {
+		return huobi.ErrCodeLoginRequired, "Signature not valid: Missing AccessKeyId or Signature"
+	}
+	if params["AccessKeyId"] != s.accessKey {
+		return huobi.ErrCodeSignatureNotValid, "Signature not valid: Incorrect Access key [Access key错误]"
+	}
+	if params["SignatureMethod"] != "HmacSHA256" || params["SignatureVersion"] != "2" {
+		return huobi.ErrCodeSignatureNotValid, "Signature not valid: Unsupported SignatureMethod or SignatureVersion"
+	}
+	timestamp, err := time.Parse("2006-01-02T15:04:05", params["Timestamp"])
+	if err != nil {
+		return huobi.ErrCodeInvalidParameter, "invalid Timestamp: " + params["Timestamp"]
+	}
+	if skew := time.Since(timestamp); skew > MaxTimestampSkew || skew < -MaxTimestampSkew {
+		return huobi.ErrCodeSignatureNotValid, "Signature not valid: Timestamp expired [时间戳过期]"
+	}
+	expected := huobi.CreateSign(params, r.Method, s.Host(), r.URL.Path, s.secretKey)
+	if signature != expected {
+		return huobi.ErrCodeSignatureNotValid, "Signature not valid: Verification failure [校验失败]"
+	}
+	return "", ""
+}
+
+// POST参数为JSON对象或对象数组, 值统一转为字符串
+func parseBody(body []byte) (map[string]string, []map[string]string) {
+	var values map[string]interface{}
+	if err := json.Unmarshal(body, &values); err == nil {
+		return stringValues(values), nil
+	}
+	var list []map[string]interface{}
+	if err := json.Unmarshal(body, &list); err != nil {
+		return map[string]string{}, nil
+	}
+	items := make([]map[string]string, 0, len(list))
+	for _, values := range list {
+		items = append(items, stringValues(values))
+	}
+	return map[string]string{}, items
+}
+
+func stringValues(values map[string]interface{}) map[string]string {
+	params := make(map[string]string, len(values))
+	for key, value := range values {
+		if s, ok := value.(string); ok {
+			params[key] = s
+		} else {
+			params[key] = fmt.Sprint(value)
+		}
+	}
+	return params
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// 返回错误, 现货接口使用err-code, err-msg, 合约接口使用err_code, err_msg
+func writeError(w http.ResponseWriter, path string, httpStatus int, code, message string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	if contract(path) {
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "error", "err_code": code, "err_msg": message, "ts": millis(time.Now())})
+		return
+	}
+	json.NewEncoder(w).Encode(map[string]string{"status": "error", "err-code": code, "err-msg": message})
+}
+
+func writeData(w http.ResponseWriter, data interface{}) {
+	writeJSON(w, map[string]interface{}{"status": "ok", "data": data})
+}
@@ -0,0 +1,174 @@
+package huobi
+
+import (
+	"errors"
+	"strings"
+	"sync"
+)
+
+// 密钥池中没有可用密钥时返回的错误
+var ErrNoAvailableKey = errors.New("huobi: no available api key")
+
+// 密钥池选择密钥的方式
+type KeyPoolStrategy int
+
+const (
+	RoundRobin KeyPoolStrategy = iota // 轮流使用
+	LeastUsed                         // 使用请求次数最少的密钥
+)
+
+// 表明密钥已过期或当前IP不在白名单的错误码, 出现时把密钥移出密钥池
+var keyRevokedErrCodes = map[string]bool{
+	"api-key-expired":      true,
+	"api-key-invalid":      true,
+	"invalid-ip":           true,
+	"ip-not-in-white-list": true,
+}
+
+// 错误信息中表明密钥不可用的关键字, 如api-signature-not-valid错误中的Incorrect Access key
+var keyRevokedMessages = []string{"key expired", "incorrect access key", "whitelist", "white list", "unauthorized ip"}
+
+// 判断错误是否表明密钥已过期或IP未加入白名单
+func IsKeyRevoked(err error) bool {
+	apiErr := AsAPIError(err)
+	if apiErr == nil {
+		return false
+	}
+	if keyRevokedErrCodes[apiErr.Code] {
+		return true
+	}
+	message := strings.ToLower(apiErr.Message)
+	for _, keyword := range keyRevokedMessages {
+		if strings.Contains(message, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// 密钥池中一个密钥的使用情况
+type KeyStats struct {
+	AccessKey string // 最近一次取出的AccessKey
+	Requests  uint64 // 签名次数
+	Disabled  bool   // 是否已移出轮换
+	LastError error  // 导致移出的错误
+}
+
+type pooledKey struct {
+	provider CredentialsProvider
+	stats    KeyStats
+}
+
+// 多个API密钥组成的密钥池, 实现CredentialsProvider, 通过WithCredentials交给Exchange
+// 每次签名按策略选出一个密钥, 请求返回密钥过期或IP未加入白名单的错误时自动移出该密钥
+// 配合TokenBucketLimiter使用时每个密钥各有一份额度, 一个密钥额度用尽不影响其他密钥
+type KeyPool struct {
+	mu       sync.Mutex
+	keys     []*pooledKey
+	strategy KeyPoolStrategy
+	next     int
+}
+
+func NewKeyPool(strategy KeyPoolStrategy, providers ...CredentialsProvider) *KeyPool {
+	pool := &KeyPool{strategy: strategy}
+	for _, provider := range providers {
+		pool.keys = append(pool.keys, &pooledKey{provider: provider})
+	}
+	return pool
+}
+
+// 由多对ak, sk创建密钥池, keys为 {ak1, sk1}, {ak2, sk2}...
+func NewStaticKeyPool(strategy KeyPoolStrategy, keys ...[2]string) *KeyPool {
+	providers := make([]CredentialsProvider, 0, len(keys))
+	for _, key := range keys {
+		providers = append(providers, NewStaticCredentials(key[0], key[1]))
+	}
+	return NewKeyPool(strategy, providers...)
+}
+
+func (p *KeyPool) Retrieve() (*Credentials, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key := p.pick()
+	if key == nil {
+		return nil, ErrNoAvailableKey
+	}
+	credentials, err := key.provider.Retrieve()
+	if err != nil {
+		return nil, err
+	}
+	key.stats.AccessKey = credentials.AccessKey
+	key.stats.Requests++
+	return credentials, nil
+}
+
+// 按策略选出一个可用的密钥, 调用方需持有锁
+func (p *KeyPool) pick() *pooledKey {
+	var picked *pooledKey
+	for i := 0; i < len(p.keys); i++ {
+		index := (p.next + i) % len(p.keys)
+		key := p.keys[index]
+		if key.stats.Disabled {
+			continue
+		}
+		if p.strategy == RoundRobin {
+			p.next = index + 1
+			return key
+		}
+		if picked == nil || key.stats.Requests < picked.stats.Requests {
+			picked = key
+		}
+	}
+	return picked
+}
+
+// 请求返回错误时由Exchange调用, 密钥不可用时将其移出轮换
+func (p *KeyPool) Report(accessKey string, err error) {
+	if accessKey == "" || !IsKeyRevoked(err) {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, key := range p.keys {
+		if key.stats.AccessKey == accessKey {
+			key.stats.Disabled = true
+			key.stats.LastError = err
+		}
+	}
+}
+
+// 把密钥重新加入轮换, 如续期或加入白名单之后
+func (p *KeyPool) Enable(accessKey string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for _, key := range p.keys {
+		if key.stats.AccessKey == accessKey {
+			key.stats.Disabled = false
+			key.stats.LastError = nil
+		}
+	}
+}
+
+// 各密钥的使用情况
+func (p *KeyPool) Stats() []KeyStats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	stats := make([]KeyStats, 0, len(p.keys))
+	for _, key := range p.keys {
+		stats = append(stats, key.stats)
+	}
+	return stats
+}
+
+// 需要知道请求结果的密钥来源, 如KeyPool
+type credentialsReporter interface {
+	Report(accessKey string, err error)
+}
+
+// 把请求错误告知密钥来源
+func (ex *Exchange) reportCredentials(accessKey string, err error) {
+	if reporter, ok := ex.credentials.(credentialsReporter); ok {
+		reporter.Report(accessKey, err)
+	}
+}
@@ -0,0 +1,77 @@
+package huobi_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/monkeybang/huobi"
+)
+
+// 从密钥池取n次密钥, 返回各AccessKey被取出的次数
+func retrieveKeys(t *testing.T, pool *huobi.KeyPool, n int) map[string]int {
+	t.Helper()
+	counts := make(map[string]int)
+	for i := 0; i < n; i++ {
+		credentials, err := pool.Retrieve()
+		if err != nil {
+			t.Fatal(err)
+		}
+		counts[credentials.AccessKey]++
+	}
+	return counts
+}
+
+func TestKeyPoolSkipsRevokedKey(t *testing.T) {
+	for _, strategy := range []huobi.KeyPoolStrategy{huobi.RoundRobin, huobi.LeastUsed} {
+		pool := huobi.NewStaticKeyPool(strategy, [2]string{"ak1", "sk1"}, [2]string{"ak2", "sk2"}, [2]string{"ak3", "sk3"})
+		if counts := retrieveKeys(t, pool, 6); counts["ak1"] != 2 || counts["ak2"] != 2 || counts["ak3"] != 2 {
+			t.Errorf("strategy %d: counts = %v, want 2 each", strategy, counts)
+		}
+
+		// 与密钥无关的错误不移出密钥
+		pool.Report("ak2", &huobi.APIError{Code: huobi.ErrCodeBalanceInsufficient})
+		pool.Report("ak2", errors.New("connection reset"))
+		if counts := retrieveKeys(t, pool, 3); counts["ak2"] != 1 {
+			t.Errorf("strategy %d: ak2 disabled by unrelated error, counts = %v", strategy, counts)
+		}
+
+		pool.Report("ak2", &huobi.APIError{Code: "api-key-expired", Message: "key expired"})
+		if counts := retrieveKeys(t, pool, 30); counts["ak2"] != 0 || counts["ak1"] != 15 || counts["ak3"] != 15 {
+			t.Errorf("strategy %d: after revoking ak2 counts = %v, want ak1 and ak3 15 each", strategy, counts)
+		}
+		stats := pool.Stats()
+		if !stats[1].Disabled || stats[0].Disabled || stats[2].Disabled || !huobi.IsKeyRevoked(stats[1].LastError) {
+			t.Errorf("strategy %d: stats = %+v", strategy, stats)
+		}
+	}
+}
+
+func TestKeyPoolNoAvailableKey(t *testing.T) {
+	pool := huobi.NewStaticKeyPool(huobi.RoundRobin, [2]string{"ak1", "sk1"})
+	retrieveKeys(t, pool, 1)
+	pool.Report("ak1", &huobi.APIError{Code: "invalid-ip"})
+	if _, err := pool.Retrieve(); !errors.Is(err, huobi.ErrNoAvailableKey) {
+		t.Errorf("err = %v, want ErrNoAvailableKey", err)
+	}
+}
+
+func TestKeyPoolLeastUsed(t *testing.T) {
+	pool := huobi.NewStaticKeyPool(huobi.LeastUsed, [2]string{"ak1", "sk1"}, [2]string{"ak2", "sk2"}, [2]string{"ak3", "sk3"})
+	retrieveKeys(t, pool, 3)
+	pool.Report("ak3", &huobi.APIError{Code: "api-key-invalid"})
+	retrieveKeys(t, pool, 10)
+
+	// 重新加入后ak3的请求数最少, 一直选它直到与其他密钥持平
+	pool.Enable("ak3")
+	if counts := retrieveKeys(t, pool, 5); counts["ak3"] != 5 {
+		t.Errorf("after enabling ak3 counts = %v, want ak3 5", counts)
+	}
+	if counts := retrieveKeys(t, pool, 3); counts["ak1"] != 1 || counts["ak2"] != 1 || counts["ak3"] != 1 {
+		t.Errorf("balanced counts = %v, want 1 each", counts)
+	}
+	for _, stats := range pool.Stats() {
+		if stats.Requests != 7 {
+			t.Errorf("%s requests = %d, want 7", stats.AccessKey, stats.Requests)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package huobi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// 日志接口, keysAndValues为成对的字段名和值, 如 logger.Warn("place error", "symbol", "btcusdt")
+// *slog.Logger直接满足该接口, zap和logrus可通过NewZapLogger, NewLogrusLogger适配
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+}
+
+// 不输出任何内容的日志, Exchange的默认值
+type NopLogger struct{}
+
+func (NopLogger) Debug(msg string, keysAndValues ...interface{}) {}
+func (NopLogger) Info(msg string, keysAndValues ...interface{})  {}
+func (NopLogger) Warn(msg string, keysAndValues ...interface{})  {}
+func (NopLogger) Error(msg string, keysAndValues ...interface{}) {}
+
+// 设置日志, 默认不输出日志
+func WithLogger(logger Logger) Option {
+	return func(ex *Exchange) {
+		ex.logger = logger
+	}
+}
+
+func (ex *Exchange) log() Logger {
+	if ex.logger != nil {
+		return ex.logger
+	}
+	return NopLogger{}
+}
+
+// *zap.SugaredLogger的方法集合
+type ZapSugaredLogger interface {
+	Debugw(msg string, keysAndValues ...interface{})
+	Infow(msg string, keysAndValues ...interface{})
+	Warnw(msg string, keysAndValues ...interface{})
+	Errorw(msg string, keysAndValues ...interface{})
+}
+
+type zapLogger struct {
+	l ZapSugaredLogger
+}
+
+// 适配zap, 传入logger.Sugar()
+func NewZapLogger(l ZapSugaredLogger) Logger {
+	return zapLogger{l: l}
+}
+
+func (z zapLogger) Debug(msg string, keysAndValues ...interface{}) { z.l.Debugw(msg, keysAndValues...) }
+func (z zapLogger) Info(msg string, keysAndValues ...interface{})  { z.l.Infow(msg, keysAndValues...) }
+func (z zapLogger) Warn(msg string, keysAndValues ...interface{})  { z.l.Warnw(msg, keysAndValues...) }
+func (z zapLogger) Error(msg string, keysAndValues ...interface{}) { z.l.Errorw(msg, keysAndValues...) }
+
+// *logrus.Logger和*logrus.Entry的方法集合
+type LogrusLogger interface {
+	Debug(args ...interface{})
+	Info(args ...interface{})
+	Warn(args ...interface{})
+	Error(args ...interface{})
+}
+
+type logrusLogger struct {
+	l LogrusLogger
+}
+
+// 适配logrus, 字段以 key=value 的形式追加在消息后
+func NewLogrusLogger(l LogrusLogger) Logger {
+	return logrusLogger{l: l}
+}
+
+func (r logrusLogger) Debug(msg string, keysAndValues ...interface{}) {
+	r.l.Debug(formatFields(msg, keysAndValues))
+}
+
+func (r logrusLogger) Info(msg string, keysAndValues ...interface{}) {
+	r.l.Info(formatFields(msg, keysAndValues))
+}
+
+func (r logrusLogger) Warn(msg string, keysAndValues ...interface{}) {
+	r.l.Warn(formatFields(msg, keysAndValues))
+}
+
+func (r logrusLogger) Error(msg string, keysAndValues ...interface{}) {
+	r.l.Error(formatFields(msg, keysAndValues))
+}
+
+// 把字段拼接为 msg k1=v1 k2=v2
+func formatFields(msg string, keysAndValues []interface{}) string {
+	var builder strings.Builder
+	builder.WriteString(msg)
+	for i := 0; i < len(keysAndValues); i += 2 {
+		builder.WriteString(" ")
+		if i+1 < len(keysAndValues) {
+			fmt.Fprintf(&builder, "%v=%v", keysAndValues[i], keysAndValues[i+1])
+		} else {
+			fmt.Fprintf(&builder, "%v", keysAndValues[i])
+		}
+	}
+	return builder.String()
+}
+
+// 以Debug级别记录每次请求的中间件, 参数已打码, 失败时以Warn级别记录错误
+func LoggingMiddleware(logger Logger) Middleware {
+	return func(next Handler) Handler {
+		return func(req *Request) (*Response, error) {
+			resp, err := next(req)
+			fields := []interface{}{"method", req.Method, "host", req.Host, "path", req.Path, "params", req.Params}
+			if resp != nil {
+				fields = append(fields, "status", resp.StatusCode, "latency", resp.Latency)
+			}
+			if err != nil {
+				logger.Warn("huobi request failed", append(fields, "error", err)...)
+			} else {
+				logger.Debug("huobi request", fields...)
+			}
+			return resp, err
+		}
+	}
+}
@@ -0,0 +1,13 @@
+//go:build go1.21
+// +build go1.21
+
+package huobi
+
+import (
+	"log/slog"
+)
+
+// 适配slog, *slog.Logger本身已满足Logger接口, 此函数仅为统一写法
+func NewSlogLogger(l *slog.Logger) Logger {
+	return l
+}
@@ -3,12 +3,12 @@ package huobi
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/shopspring/decimal"
 	"github.com/spf13/cast"
-	"github.com/tidwall/gjson"
-	"log"
 	"strconv"
 )
 
+// 默认API地址, 未通过Option指定Endpoints的Exchange及包级行情函数使用
 var MARKET_URL = `https://api.huobi.fm`
 var TRADE_URL = `https://api.huobi.fm`
 var HOST_NAME = `api.huobi.fm`
@@ -18,29 +18,41 @@ var HOST_CONTRACT = `api.hbdm.com`
 // 获取聚合行情
 // strSymbol: 交易对, btcusdt, bccbtc......
 // return: TickReturn对象
-func GetTicker(strSymbol string) *TickerReturn {
+func GetTicker(strSymbol string) (*TickerReturn, error) {
+	return defaultExchange().GetTicker(strSymbol)
+}
+
+// 同GetTicker, 使用Exchange自身的行情地址
+func (ex *Exchange) GetTicker(strSymbol string) (*TickerReturn, error) {
 	tickerReturn := &TickerReturn{}
 
 	mapParams := make(map[string]string)
 	mapParams["symbol"] = strSymbol
 
 	strRequestUrl := "/market/detail/merged"
-	strUrl := MARKET_URL + strRequestUrl
+	strUrl := ex.endpoints.MarketURL + strRequestUrl
 
-	jsonTickReturn := HttpGetRequest(strUrl, mapParams)
-	err := json.Unmarshal([]byte(jsonTickReturn), tickerReturn)
+	jsonTickReturn, err := ex.httpGet(ex.Context(), strUrl, mapParams)
 	if err != nil {
-		log.Print(err)
-		return nil
+		return nil, err
 	}
-	return tickerReturn
+	err = json.Unmarshal([]byte(jsonTickReturn), tickerReturn)
+	if err != nil {
+		return nil, err
+	}
+	return tickerReturn, nil
 }
 
 // 获取交易深度信息
 // strSymbol: 交易对, btcusdt, bccbtc......
 // strType: Depth类型, step0、step1......stpe5 (合并深度0-5, 0时不合并)
 // return: MarketDepthReturn对象
-func GetMarketDepth(strSymbol, strType string) *MarketDepthReturn {
+func GetMarketDepth(strSymbol, strType string) (*MarketDepthReturn, error) {
+	return defaultExchange().GetMarketDepth(strSymbol, strType)
+}
+
+// 同GetMarketDepth, 使用Exchange自身的行情地址
+func (ex *Exchange) GetMarketDepth(strSymbol, strType string) (*MarketDepthReturn, error) {
 	marketDepthReturn := &MarketDepthReturn{}
 
 	mapParams := make(map[string]string)
@@ -48,41 +60,57 @@ func GetMarketDepth(strSymbol, strType string) *MarketDepthReturn {
 	mapParams["type"] = strType
 
 	strRequestUrl := "/market/depth"
-	strUrl := MARKET_URL + strRequestUrl
+	strUrl := ex.endpoints.MarketURL + strRequestUrl
 
-	jsonMarketDepthReturn := HttpGetRequest(strUrl, mapParams)
-	err := json.Unmarshal([]byte(jsonMarketDepthReturn), &marketDepthReturn)
+	jsonMarketDepthReturn, err := ex.httpGet(ex.Context(), strUrl, mapParams)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(jsonMarketDepthReturn), marketDepthReturn)
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return marketDepthReturn
+	return marketDepthReturn, nil
 }
 
 // 获取交易细节信息
 // strSymbol: 交易对, btcusdt, bccbtc......
 // return: TradeDetailReturn对象
-func GetTradeDetail(strSymbol string) *TradeDetailReturn {
+func GetTradeDetail(strSymbol string) (*TradeDetailReturn, error) {
+	return defaultExchange().GetTradeDetail(strSymbol)
+}
+
+// 同GetTradeDetail, 使用Exchange自身的行情地址
+func (ex *Exchange) GetTradeDetail(strSymbol string) (*TradeDetailReturn, error) {
 	tradeDetailReturn := &TradeDetailReturn{}
 
 	mapParams := make(map[string]string)
 	mapParams["symbol"] = strSymbol
 
 	strRequestUrl := "/market/trade"
-	strUrl := MARKET_URL + strRequestUrl
+	strUrl := ex.endpoints.MarketURL + strRequestUrl
 
-	jsonTradeDetailReturn := HttpGetRequest(strUrl, mapParams)
-	err := json.Unmarshal([]byte(jsonTradeDetailReturn), &tradeDetailReturn)
+	jsonTradeDetailReturn, err := ex.httpGet(ex.Context(), strUrl, mapParams)
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return tradeDetailReturn
+	err = json.Unmarshal([]byte(jsonTradeDetailReturn), tradeDetailReturn)
+	if err != nil {
+		return nil, err
+	}
+	return tradeDetailReturn, nil
 }
 
 // 批量获取最近的交易记录
 // strSymbol: 交易对, btcusdt, bccbtc......
 // nSize: 获取交易记录的数量, 范围1-2000
 // return: TradeReturn对象
-func GetTrade(strSymbol string, nSize int) *TradeReturn {
+func GetTrade(strSymbol string, nSize int) (*TradeReturn, error) {
+	return defaultExchange().GetTrade(strSymbol, nSize)
+}
+
+// 同GetTrade, 使用Exchange自身的行情地址
+func (ex *Exchange) GetTrade(strSymbol string, nSize int) (*TradeReturn, error) {
 	tradeReturn := &TradeReturn{}
 
 	mapParams := make(map[string]string)
@@ -90,37 +118,53 @@ func GetTrade(strSymbol string, nSize int) *TradeReturn {
 	mapParams["size"] = strconv.Itoa(nSize)
 
 	strRequestUrl := "/market/history/trade"
-	strUrl := MARKET_URL + strRequestUrl
+	strUrl := ex.endpoints.MarketURL + strRequestUrl
 
-	jsonTradeReturn := HttpGetRequest(strUrl, mapParams)
-	err := json.Unmarshal([]byte(jsonTradeReturn), &tradeReturn)
+	jsonTradeReturn, err := ex.httpGet(ex.Context(), strUrl, mapParams)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(jsonTradeReturn), tradeReturn)
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return tradeReturn
+	return tradeReturn, nil
 }
 
 // 获取Market Detail 24小时成交量数据
 // strSymbol: 交易对, btcusdt, bccbtc......
 // return: MarketDetailReturn对象
-func GetMarketDetail(strSymbol string) *MarketDetailReturn {
+func GetMarketDetail(strSymbol string) (*MarketDetailReturn, error) {
+	return defaultExchange().GetMarketDetail(strSymbol)
+}
+
+// 同GetMarketDetail, 使用Exchange自身的行情地址
+func (ex *Exchange) GetMarketDetail(strSymbol string) (*MarketDetailReturn, error) {
 	marketDetailReturn := &MarketDetailReturn{}
 
 	mapParams := make(map[string]string)
 	mapParams["symbol"] = strSymbol
 
 	strRequestUrl := "/market/detail"
-	strUrl := MARKET_URL + strRequestUrl
+	strUrl := ex.endpoints.MarketURL + strRequestUrl
 
-	jsonMarketDetailReturn := HttpGetRequest(strUrl, mapParams)
-	err := json.Unmarshal([]byte(jsonMarketDetailReturn), &marketDetailReturn)
+	jsonMarketDetailReturn, err := ex.httpGet(ex.Context(), strUrl, mapParams)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(jsonMarketDetailReturn), marketDetailReturn)
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return marketDetailReturn
+	return marketDetailReturn, nil
 }
 
-func GetKline(period, strSymbol string, size int64) *KLineReturn {
+func GetKline(period, strSymbol string, size int64) (*KLineReturn, error) {
+	return defaultExchange().GetKline(period, strSymbol, size)
+}
+
+// 同GetKline, 使用Exchange自身的行情地址
+func (ex *Exchange) GetKline(period, strSymbol string, size int64) (*KLineReturn, error) {
 	kLineReturn := &KLineReturn{}
 
 	mapParams := make(map[string]string)
@@ -129,13 +173,16 @@ func GetKline(period, strSymbol string, size int64) *KLineReturn {
 	mapParams["size"] = cast.ToString(size)
 
 	strRequestUrl := "/market/history/kline"
-	strUrl := MARKET_URL + strRequestUrl
-	jsonMarketKlineReturn := HttpGetRequest(strUrl, mapParams)
-	err := json.Unmarshal([]byte(jsonMarketKlineReturn), &kLineReturn)
+	strUrl := ex.endpoints.MarketURL + strRequestUrl
+	jsonMarketKlineReturn, err := ex.httpGet(ex.Context(), strUrl, mapParams)
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return kLineReturn
+	err = json.Unmarshal([]byte(jsonMarketKlineReturn), kLineReturn)
+	if err != nil {
+		return nil, err
+	}
+	return kLineReturn, nil
 }
 
 //----------------------------------------
@@ -143,50 +190,98 @@ func GetKline(period, strSymbol string, size int64) *KLineReturn {
 
 // 查询系统支持的所有交易及精度
 // return: SymbolsReturn对象
-func GetSymbols() *SymbolsReturn {
+func GetSymbols() (*SymbolsReturn, error) {
+	return defaultExchange().QuerySymbols()
+}
+
+// 同GetSymbols, 使用Exchange自身的交易地址(TradeURL)
+func (ex *Exchange) QuerySymbols() (*SymbolsReturn, error) {
 	symbolsReturn := &SymbolsReturn{}
 
 	strRequestUrl := "/v1/common/symbols"
-	strUrl := TRADE_URL + strRequestUrl
+	strUrl := ex.endpoints.TradeURL + strRequestUrl
 
-	jsonSymbolsReturn := HttpGetRequest(strUrl, nil)
-	err := json.Unmarshal([]byte(jsonSymbolsReturn), &symbolsReturn)
+	jsonSymbolsReturn, err := ex.httpGet(ex.Context(), strUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(jsonSymbolsReturn), symbolsReturn)
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return symbolsReturn
+	return symbolsReturn, nil
+}
+
+// 查询交易对的v2参考信息, 包括交易状态、手续费精度、杠杆倍数等
+// return: SymbolsV2Return对象
+func GetSymbolsV2() (*SymbolsV2Return, error) {
+	return defaultExchange().QuerySymbolsV2()
+}
+
+// 同GetSymbolsV2, 使用Exchange自身的交易地址(TradeURL)
+func (ex *Exchange) QuerySymbolsV2() (*SymbolsV2Return, error) {
+	symbolsReturn := &SymbolsV2Return{}
+
+	strRequestUrl := "/v2/settings/common/symbols"
+	strUrl := ex.endpoints.TradeURL + strRequestUrl
+
+	jsonSymbolsReturn, err := ex.httpGet(ex.Context(), strUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(jsonSymbolsReturn), symbolsReturn)
+	if err != nil {
+		return nil, err
+	}
+	return symbolsReturn, nil
 }
 
 // 查询系统支持的所有币种
 // return: CurrencysReturn对象
-func GetCurrencys() *CurrencysReturn {
+func GetCurrencys() (*CurrencysReturn, error) {
+	return defaultExchange().GetCurrencys()
+}
+
+// 同GetCurrencys, 使用Exchange自身的交易地址(TradeURL)
+func (ex *Exchange) GetCurrencys() (*CurrencysReturn, error) {
 	currencysReturn := &CurrencysReturn{}
 
 	strRequestUrl := "/v1/common/currencys"
-	strUrl := TRADE_URL + strRequestUrl
+	strUrl := ex.endpoints.TradeURL + strRequestUrl
 
-	jsonCurrencysReturn := HttpGetRequest(strUrl, nil)
-	err := json.Unmarshal([]byte(jsonCurrencysReturn), &currencysReturn)
+	jsonCurrencysReturn, err := ex.httpGet(ex.Context(), strUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(jsonCurrencysReturn), currencysReturn)
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return currencysReturn
+	return currencysReturn, nil
 }
 
 // 查询系统当前时间戳
 // return: TimestampReturn对象
-func GetTimestamp() *TimestampReturn {
+func GetTimestamp() (*TimestampReturn, error) {
+	return defaultExchange().GetTimestamp()
+}
+
+// 同GetTimestamp, 使用Exchange自身的交易地址(TradeURL)
+func (ex *Exchange) GetTimestamp() (*TimestampReturn, error) {
 	timestampReturn := &TimestampReturn{}
 
 	strRequest := "/v1/common/timestamp"
-	strUrl := TRADE_URL + strRequest
+	strUrl := ex.endpoints.TradeURL + strRequest
 
-	jsonTimestampReturn := HttpGetRequest(strUrl, nil)
-	err := json.Unmarshal([]byte(jsonTimestampReturn), &timestampReturn)
+	jsonTimestampReturn, err := ex.httpGet(ex.Context(), strUrl, nil)
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return timestampReturn
+	err = json.Unmarshal([]byte(jsonTimestampReturn), timestampReturn)
+	if err != nil {
+		return nil, err
+	}
+	return timestampReturn, nil
 }
 
 //------------------------------------------------------------------------------------------
@@ -194,33 +289,35 @@ func GetTimestamp() *TimestampReturn {
 
 // 查询当前用户的所有账户, 根据包含的私钥查询
 // return: AccountsReturn对象
-func (ex *Exchange) GetAccounts() *AccountsReturn {
+func (ex *Exchange) GetAccounts() (*AccountsReturn, error) {
 	accountsReturn := &AccountsReturn{}
 	strRequest := "/v1/account/accounts"
-	jsonAccountsReturn := ex.ApiKeyGet(make(map[string]string), strRequest)
-	err := json.Unmarshal([]byte(jsonAccountsReturn), &accountsReturn)
+	jsonAccountsReturn, err := ex.ApiKeyGet(make(map[string]string), strRequest)
 	if err != nil {
-		log.Println(err, strRequest)
+		return nil, err
 	}
-	return accountsReturn
+	err = json.Unmarshal([]byte(jsonAccountsReturn), accountsReturn)
+	if err != nil {
+		return nil, err
+	}
+	return accountsReturn, nil
 }
 
 // 根据账户ID查询账户余额
 // nAccountID: 账户ID, 不知道的话可以通过GetAccounts()获取, 可以只现货账户, C2C账户, 期货账户
 // return: BalanceReturn对象
-func (ex *Exchange) GetAccountBalance(strAccountID string) *BalanceReturn {
+func (ex *Exchange) GetAccountBalance(strAccountID string) (*BalanceReturn, error) {
 	balanceReturn := &BalanceReturn{}
 	strRequest := fmt.Sprintf("/v1/account/accounts/%s/balance", strAccountID)
-	jsonBanlanceReturn := ex.ApiKeyGet(make(map[string]string), strRequest)
-	if result := gjson.Get(jsonBanlanceReturn, "data"); !result.Exists() {
-		log.Println(jsonBanlanceReturn)
+	jsonBanlanceReturn, err := ex.ApiKeyGet(make(map[string]string), strRequest)
+	if err != nil {
+		return nil, err
 	}
-	err := json.Unmarshal([]byte(jsonBanlanceReturn), &balanceReturn)
+	err = json.Unmarshal([]byte(jsonBanlanceReturn), balanceReturn)
 	if err != nil {
-		log.Println(err, strRequest)
+		return nil, err
 	}
-
-	return balanceReturn
+	return balanceReturn, nil
 }
 
 //------------------------------------------------------------------------------------------
@@ -229,60 +326,127 @@ func (ex *Exchange) GetAccountBalance(strAccountID string) *BalanceReturn {
 // 下单
 // placeRequestParams: 下单信息
 // return: PlaceReturn对象
-func (ex *Exchange) Place(placeRequestParams *PlaceRequestParams) *PlaceReturn {
+func (ex *Exchange) Place(placeRequestParams *PlaceRequestParams) (*PlaceReturn, error) {
 	placeReturn := &PlaceReturn{}
 
+	mapParams, err := ex.placeParams(placeRequestParams)
+	if err != nil {
+		return nil, err
+	}
+
+	strRequest := "/v1/order/orders/place"
+	jsonPlaceReturn, err := ex.ApiKeyPost(mapParams, strRequest)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal([]byte(jsonPlaceReturn), placeReturn)
+	if err != nil {
+		return nil, err
+	}
+	return placeReturn, nil
+}
+
+// 校验下单参数并转换为请求参数, Place和BatchPlace共用
+func (ex *Exchange) placeParams(placeRequestParams *PlaceRequestParams) (map[string]string, error) {
+	if err := placeRequestParams.Validate(); err != nil {
+		return nil, err
+	}
+	// 设置了校验器时先在本地校验, 不符合交易对规则的订单不发出请求
+	if ex.validator != nil {
+		validated, err := ex.ValidateOrder(placeRequestParams)
+		if err != nil {
+			return nil, err
+		}
+		placeRequestParams = validated
+	}
+
 	mapParams := make(map[string]string)
 	mapParams["account-id"] = placeRequestParams.AccountID
-	mapParams["amount"] = placeRequestParams.Amount
-	if 0 < len(placeRequestParams.Price) {
-		mapParams["price"] = placeRequestParams.Price
+	mapParams["amount"] = placeRequestParams.Amount.String()
+	if !placeRequestParams.Price.IsZero() {
+		mapParams["price"] = placeRequestParams.Price.String()
+	}
+	// 已知交易对时按精度格式化: 数量向下截断, 市价买单的amount为金额, 按金额精度截断
+	// 价格只向对下单方有利的方向调整, 买价向下、卖价向上, 不会以超出下单方限价的价格成交
+	if symbol, ok := ex.symbols.Get(placeRequestParams.Symbol); ok {
+		buy := placeRequestParams.Type.IsBuy()
+		if placeRequestParams.Type.IsMarket() && buy {
+			mapParams["amount"] = symbol.FormatValue(placeRequestParams.Amount)
+		} else {
+			mapParams["amount"] = symbol.FormatAmount(placeRequestParams.Amount)
+		}
+		if !placeRequestParams.Price.IsZero() {
+			mapParams["price"] = adjustPrice(placeRequestParams.Price, symbol.PricePrecision, buy).StringFixed(int32(symbol.PricePrecision))
+		}
 	}
 	if 0 < len(placeRequestParams.Source) {
 		mapParams["source"] = placeRequestParams.Source
 	}
 	mapParams["symbol"] = placeRequestParams.Symbol
-	mapParams["type"] = placeRequestParams.Type
-
-	strRequest := "/v1/order/orders/place"
-	jsonPlaceReturn := ex.ApiKeyPost(mapParams, strRequest)
-	err := json.Unmarshal([]byte(jsonPlaceReturn), &placeReturn)
-	if err != nil {
-		log.Println(err)
+	mapParams["type"] = string(placeRequestParams.Type)
+	if placeRequestParams.Type.IsStop() {
+		// 触发价没有对下单方有利的调整方向, 原样发送, 精度不符时由校验器或服务端拒绝
+		mapParams["stop-price"] = placeRequestParams.StopPrice.String()
+		mapParams["operator"] = string(placeRequestParams.Operator)
 	}
-	return placeReturn
+	if 0 < len(placeRequestParams.ClientOrderID) {
+		mapParams["client-order-id"] = placeRequestParams.ClientOrderID
+	}
+	return mapParams, nil
 }
 
 // 申请撤销一个订单请求
 // strOrderID: 订单ID
 // return: PlaceReturn对象
-func (ex *Exchange) SubmitCancel(strOrderID string) *PlaceReturn {
+func (ex *Exchange) SubmitCancel(strOrderID string) (*PlaceReturn, error) {
 	placeReturn := &PlaceReturn{}
 
 	strRequest := fmt.Sprintf("/v1/order/orders/%s/submitcancel", strOrderID)
-	jsonPlaceReturn := ex.ApiKeyPost(make(map[string]string), strRequest)
-	err := json.Unmarshal([]byte(jsonPlaceReturn), &placeReturn)
+	jsonPlaceReturn, err := ex.ApiKeyPost(make(map[string]string), strRequest)
 	if err != nil {
-		log.Println(err)
+		return nil, err
 	}
-	return placeReturn
+	err = json.Unmarshal([]byte(jsonPlaceReturn), placeReturn)
+	if err != nil {
+		return nil, err
+	}
+	return placeReturn, nil
 }
 
+// ETP最新净值
 type EtpMarket struct {
-	Symbol  string
-	Nav     float64
-	NavTime int64
+	Symbol  string          `json:"symbol"`
+	Nav     decimal.Decimal `json:"nav"`
+	NavTime int64           `json:"navTime"`
 }
 
-func GetEtpNav(symbol string) float64 {
+type EtpMarketReturn struct {
+	Status string    `json:"status"`
+	Ch     string    `json:"ch"`
+	Ts     int64     `json:"ts"`
+	Tick   EtpMarket `json:"tick"`
+}
 
+func GetEtpNav(symbol string) (decimal.Decimal, error) {
+	return defaultExchange().GetEtpNav(symbol)
+}
+
+// 同GetEtpNav, 使用Exchange自身的行情地址
+func (ex *Exchange) GetEtpNav(symbol string) (decimal.Decimal, error) {
 	mapParams := make(map[string]string)
 	mapParams["symbol"] = symbol
 
 	strRequestUrl := "/market/etp"
-	strUrl := MARKET_URL + strRequestUrl
-
-	jsonMarketDetailReturn := HttpGetRequest(strUrl, mapParams)
-	return gjson.Get(jsonMarketDetailReturn, "tick.nav").Float()
+	strUrl := ex.endpoints.MarketURL + strRequestUrl
 
+	jsonEtpMarketReturn, err := ex.httpGet(ex.Context(), strUrl, mapParams)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	etpMarketReturn := &EtpMarketReturn{}
+	err = json.Unmarshal([]byte(jsonEtpMarketReturn), etpMarketReturn)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return etpMarketReturn.Tick.Nav, nil
 }
@@ -0,0 +1,66 @@
+package huobi_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/monkeybang/huobi"
+	"github.com/monkeybang/huobi/huobitest"
+	"github.com/shopspring/decimal"
+)
+
+func TestPlaceFormatsByPrecision(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	server.SetSymbols(append(huobitest.DefaultSymbols(), &huobi.SymbolsData{Symbol: "htusdt", State: "online", BaseCurrency: "ht", QuoteCurrency: "usdt",
+		PricePrecision: 4, AmountPrecision: 0, ValuePrecision: 8}))
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	d := decimal.RequireFromString
+	tests := []struct {
+		name      string
+		builder   *huobi.OrderBuilder
+		amount    string
+		price     string
+		stopPrice string
+	}{
+		{"buy-market value precision", huobi.NewOrderBuilder("htusdt", huobi.SideBuy).Market(d("10.5")), "10.50000000", "", ""},
+		{"sell-market amount precision", huobi.NewOrderBuilder("htusdt", huobi.SideSell).Market(d("10.5")), "10", "", ""},
+		{"buy-limit price rounds down", huobi.NewOrderBuilder("btcusdt", huobi.SideBuy).Limit(d("0.3"), d("100.005")), "0.300000", "100.00", ""},
+		{"sell-limit price rounds up", huobi.NewOrderBuilder("btcusdt", huobi.SideSell).Limit(d("0.3"), d("100.001")), "0.300000", "100.01", ""},
+		{"stop-price unchanged", huobi.NewOrderBuilder("btcusdt", huobi.SideBuy).StopLimit(d("0.3"), d("100"), d("99.995"), huobi.OperatorLTE), "0.300000", "100.00", "99.995"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if _, err := ex.PlaceBuilt(test.builder); err != nil {
+				t.Fatal(err)
+			}
+			requests := server.Requests()
+			request := requests[len(requests)-1]
+			if request.Method != http.MethodPost || request.Path != "/v1/order/orders/place" {
+				t.Fatalf("last request = %s %s", request.Method, request.Path)
+			}
+			if request.Body["amount"] != test.amount || request.Body["price"] != test.price || request.Body["stop-price"] != test.stopPrice {
+				t.Errorf("amount, price, stop-price = %q, %q, %q, want %q, %q, %q", request.Body["amount"], request.Body["price"],
+					request.Body["stop-price"], test.amount, test.price, test.stopPrice)
+			}
+		})
+	}
+}
+
+func TestGetEtpNav(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	server.SetResponse(http.MethodGet, "/market/etp", `{"ch":"market.btc3lusdt.etp","status":"ok","ts":1,"tick":{"symbol":"btc3lusdt","nav":12.3456789012345678,"navTime":1}}`)
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	nav, err := ex.GetEtpNav("btc3lusdt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := decimal.RequireFromString("12.3456789012345678"); !nav.Equal(want) {
+		t.Errorf("nav = %s, want %s", nav, want)
+	}
+}
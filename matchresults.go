@@ -0,0 +1,201 @@
+package huobi
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// 每页最多的成交明细数
+	MatchResultsLimit = 500
+	// /v1/order/matchresults每次查询的最大时间范围
+	MatchResultsWindow = 48 * time.Hour
+)
+
+// 查询订单的成交明细
+func (ex *Exchange) GetMatchResults(orderId string) ([]MatchResult, error) {
+	strRequest := "/v1/order/orders/" + orderId + "/matchresults"
+	str, err := ex.ApiKeyGet(make(map[string]string), strRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	matchResultsReturn := &MatchResultsReturn{}
+	err = json.Unmarshal([]byte(str), matchResultsReturn)
+	if err != nil {
+		return nil, err
+	}
+	return matchResultsReturn.Data, nil
+}
+
+// /v1/order/matchresults的查询条件, 零值表示不限
+type MatchResultSearch struct {
+	Symbol    string      // 交易对, 必填
+	Types     []OrderType // 订单类型
+	StartTime time.Time   // 开始时间, 为零时由服务端默认为EndTime之前48小时
+	EndTime   time.Time   // 结束时间, 为零时为当前时间
+	Size      int         // 每页的成交明细数, 最大500, 默认100
+}
+
+// 从新到旧逐笔遍历成交明细, 一页取完后自动请求下一页
+// 时间范围超过MatchResultsWindow时按窗口从后往前依次查询
+type MatchResultIterator struct {
+	pager
+	page []MatchResult
+}
+
+// fetch请求下一页, 返回该页成交明细和是否还有下一页
+func newMatchResultIterator(fetch func() ([]MatchResult, bool, error)) *MatchResultIterator {
+	it := &MatchResultIterator{}
+	it.pager = newPager(func() (int, bool, error) {
+		page, more, err := fetch()
+		it.page = page
+		return len(page), more, err
+	})
+	return it
+}
+
+// 移动到下一笔成交明细, 没有更多成交明细或出错时返回false
+func (it *MatchResultIterator) Next() bool {
+	return it.next()
+}
+
+// 当前成交明细
+func (it *MatchResultIterator) MatchResult() *MatchResult {
+	if i := it.current(); i >= 0 {
+		return &it.page[i]
+	}
+	return nil
+}
+
+// 遍历中遇到的错误
+func (it *MatchResultIterator) Err() error {
+	return it.err
+}
+
+// 取出剩余的所有成交明细, 出错时返回已取得的成交明细和错误
+func (it *MatchResultIterator) All() ([]MatchResult, error) {
+	var results []MatchResult
+	for it.Next() {
+		results = append(results, *it.MatchResult())
+	}
+	return results, it.Err()
+}
+
+// 按条件查询成交明细, 每个时间窗口内通过from和size自动翻页
+func (ex *Exchange) SearchMatchResults(search *MatchResultSearch) *MatchResultIterator {
+	params := make(map[string]string)
+	params["symbol"] = search.Symbol
+	if len(search.Types) > 0 {
+		types := make([]string, 0, len(search.Types))
+		for _, orderType := range search.Types {
+			types = append(types, string(orderType))
+		}
+		sort.Strings(types)
+		params["types"] = strings.Join(types, ",")
+	}
+	params["direct"] = string(PageNext)
+	size := search.Size
+	if size <= 0 {
+		size = 100
+	}
+	if size > MatchResultsLimit {
+		size = MatchResultsLimit
+	}
+	params["size"] = strconv.Itoa(size)
+
+	end := search.EndTime
+	if end.IsZero() {
+		end = time.Now()
+	}
+	windows := &timeWindows{start: search.StartTime, end: end, size: MatchResultsWindow}
+	hasWindow := windows.next(params)
+
+	cursor := &fromCursor{size: size}
+	it := newMatchResultIterator(func() ([]MatchResult, bool, error) {
+		for {
+			cursor.apply(params)
+			matchResultsReturn, err := ex.QueryMatchResults(params)
+			if err != nil {
+				return nil, false, err
+			}
+			results := matchResultsReturn.Data
+			ids := make([]int64, len(results))
+			for i := range results {
+				ids[i] = results[i].ID
+			}
+			skip, full := cursor.advance(ids)
+			results = results[skip:]
+			if full {
+				return results, true, nil
+			}
+			// 当前窗口已取完, 进入前一个窗口
+			cursor.reset()
+			if !windows.next(params) {
+				return results, false, nil
+			}
+			if len(results) > 0 {
+				return results, true, nil
+			}
+		}
+	})
+	it.more = hasWindow
+	return it
+}
+
+// 把[start, end]从后往前切分为不超过size的时间窗口, 窗口首尾都包含, 相邻窗口间隔1毫秒
+// start为零时只有一个窗口, 不设置start-time, 由服务端决定开始时间
+type timeWindows struct {
+	start   time.Time
+	end     time.Time
+	size    time.Duration
+	current time.Time // 当前窗口的开始时间
+	started bool
+}
+
+// 进入下一个窗口, 把窗口写入params的start-time和end-time, 没有更多窗口时返回false
+func (w *timeWindows) next(params map[string]string) bool {
+	end := w.end
+	if w.started {
+		if w.start.IsZero() {
+			return false
+		}
+		end = w.current.Add(-time.Millisecond)
+	}
+	if !w.start.IsZero() && end.Before(w.start) {
+		return false
+	}
+	w.started = true
+
+	start := w.start
+	if !start.IsZero() && end.Sub(start) >= w.size {
+		start = end.Add(-w.size + time.Millisecond)
+	}
+	w.current = start
+	if start.IsZero() {
+		delete(params, "start-time")
+	} else {
+		params["start-time"] = strconv.FormatInt(unixMillis(start), 10)
+	}
+	params["end-time"] = strconv.FormatInt(unixMillis(end), 10)
+	return true
+}
+
+// 请求一页/v1/order/matchresults, params为原始请求参数
+func (ex *Exchange) QueryMatchResults(params map[string]string) (*MatchResultsReturn, error) {
+	strRequest := "/v1/order/matchresults"
+	str, err := ex.ApiKeyGet(params, strRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	matchResultsReturn := &MatchResultsReturn{}
+	err = json.Unmarshal([]byte(str), matchResultsReturn)
+	if err != nil {
+		return nil, err
+	}
+	return matchResultsReturn, nil
+}
@@ -0,0 +1,123 @@
+package huobi_test
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/monkeybang/huobi"
+	"github.com/monkeybang/huobi/huobitest"
+	"github.com/shopspring/decimal"
+)
+
+func unixMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+// 下一笔symbol买单, 在at分三次成交
+func fillAt(t *testing.T, server *huobitest.Server, ex *huobi.Exchange, symbol string, at time.Time) {
+	t.Helper()
+	d := decimal.RequireFromString
+	orderID, err := ex.PlaceBuilt(huobi.NewOrderBuilder(symbol, huobi.SideBuy).Limit(d("1"), d("100")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	id, _ := strconv.ParseInt(orderID, 10, 64)
+	for _, filled := range []string{"0.2", "0.5", "1"} {
+		if !server.FillOrderAt(id, d(filled), at) {
+			t.Fatalf("fill order %d failed", id)
+		}
+	}
+}
+
+func TestSearchMatchResultsAcrossWindows(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	end := time.Now().Truncate(time.Millisecond)
+	start := end.Add(-150 * time.Hour)
+	window := huobi.MatchResultsWindow
+	ms := time.Millisecond
+
+	// 范围之外和其他交易对的成交不应返回
+	fillAt(t, server, ex, "btcusdt", start.Add(-ms))
+	fillAt(t, server, ex, "ethusdt", end.Add(-time.Hour))
+	// 从旧到新成交, 包含正好落在窗口边界两侧的成交
+	times := []time.Time{
+		start,
+		end.Add(-3*window - time.Hour),
+		end.Add(-2 * window),
+		end.Add(-2*window + ms),
+		end.Add(-window - time.Hour),
+		end.Add(-window),
+		end.Add(-window + ms),
+		end.Add(-time.Hour),
+		end,
+	}
+	for _, at := range times {
+		fillAt(t, server, ex, "btcusdt", at)
+	}
+
+	var want []int64
+	for _, result := range server.MatchResults() {
+		if result.Symbol == "btcusdt" && result.CreatedAt >= unixMillis(start) {
+			want = append([]int64{result.ID}, want...)
+		}
+	}
+
+	before := len(server.Requests())
+	results, err := ex.SearchMatchResults(&huobi.MatchResultSearch{Symbol: "btcusdt", StartTime: start, EndTime: end, Size: 4}).All()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int64]bool)
+	for _, result := range results {
+		if seen[result.ID] {
+			t.Fatalf("match result %d returned twice", result.ID)
+		}
+		seen[result.ID] = true
+	}
+	if len(results) != len(want) {
+		t.Fatalf("got %d match results, want %d", len(results), len(want))
+	}
+	for i, result := range results {
+		if result.ID != want[i] {
+			t.Fatalf("match result %d = %d, want %d", i, result.ID, want[i])
+		}
+	}
+
+	// 窗口从end开始往前首尾相接, 每个窗口不超过48小时, 最后一个窗口从start开始
+	next := unixMillis(end)
+	var current int64
+	windows := 0
+	for _, request := range server.Requests()[before:] {
+		if request.Path != "/v1/order/matchresults" {
+			continue
+		}
+		startTime, _ := strconv.ParseInt(request.Query.Get("start-time"), 10, 64)
+		endTime, _ := strconv.ParseInt(request.Query.Get("end-time"), 10, 64)
+		if request.Query.Get("from") != "" {
+			if endTime != current {
+				t.Errorf("page request end-time = %d, want %d", endTime, current)
+			}
+			continue
+		}
+		if endTime != next {
+			t.Errorf("window %d end-time = %d, want %d", windows, endTime, next)
+		}
+		if endTime-startTime >= int64(window/ms) {
+			t.Errorf("window %d [%d, %d] longer than 48h", windows, startTime, endTime)
+		}
+		current = endTime
+		next = startTime - 1
+		windows++
+	}
+	if next != unixMillis(start)-1 {
+		t.Errorf("last window starts at %d, want %d", next+1, unixMillis(start))
+	}
+	if windows != 4 {
+		t.Errorf("queried %d windows, want 4", windows)
+	}
+}
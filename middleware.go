@@ -0,0 +1,135 @@
+package huobi
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// 签名参数中需要打码的字段
+var redactedParams = []string{"AccessKeyId", "Signature"}
+
+// 中间件看到的一次请求
+type Request struct {
+	Method string            // GET, POST
+	Host   string            // 请求的主机
+	Path   string            // API路由路径, 不含查询参数
+	Params map[string]string // 查询参数, AccessKeyId和Signature已打码
+	Body   string            // POST请求体
+	Group  string            // 限频分组, 见RateLimitMarket等
+
+	// 实际发出的请求, 中间件可以替换它, 如注入追踪用的ctx或请求头
+	HTTPRequest *http.Request
+}
+
+// 中间件看到的一次响应, 网络错误时为nil
+type Response struct {
+	StatusCode int
+	Header     http.Header
+	Body       string
+	Latency    time.Duration // 从发出请求到读完响应的耗时
+}
+
+// 处理一次请求, 返回的error与Exchange方法返回的相同
+type Handler func(req *Request) (*Response, error)
+
+// 中间件, 包装next以观察或修改请求与响应, 用于日志、监控、链路追踪等
+type Middleware func(next Handler) Handler
+
+// 追加中间件, 先添加的在外层, 最先看到请求
+func WithMiddleware(middlewares ...Middleware) Option {
+	return func(ex *Exchange) {
+		ex.middlewares = append(ex.middlewares[:len(ex.middlewares):len(ex.middlewares)], middlewares...)
+	}
+}
+
+// 返回参数的副本, 其中的AccessKeyId和Signature替换为***
+func RedactParams(params map[string]string) map[string]string {
+	redacted := make(map[string]string, len(params))
+	for key, value := range params {
+		redacted[key] = value
+	}
+	for _, key := range redactedParams {
+		if _, ok := redacted[key]; ok {
+			redacted[key] = "***"
+		}
+	}
+	return redacted
+}
+
+func newMiddlewareRequest(request *http.Request, group string) *Request {
+	params := make(map[string]string)
+	for key, values := range request.URL.Query() {
+		if len(values) > 0 {
+			params[key] = values[0]
+		}
+	}
+
+	req := &Request{
+		Method:      request.Method,
+		Host:        request.URL.Host,
+		Path:        request.URL.Path,
+		Params:      RedactParams(params),
+		Group:       group,
+		HTTPRequest: request,
+	}
+	if request.GetBody != nil {
+		if body, err := request.GetBody(); err == nil {
+			bytes, _ := ioutil.ReadAll(body)
+			body.Close()
+			req.Body = string(bytes)
+		}
+	}
+	return req
+}
+
+// 中间件链最内层的处理: 发出HTTP请求并计时
+func (ex *Exchange) roundTrip(req *Request) (*Response, error) {
+	start := time.Now()
+	response, body, err := doRequest(ex.client(), req.HTTPRequest)
+	if response == nil {
+		return nil, err
+	}
+	return &Response{
+		StatusCode: response.StatusCode,
+		Header:     response.Header,
+		Body:       body,
+		Latency:    time.Since(start),
+	}, err
+}
+
+// 按添加顺序把中间件包在handler外面
+func (ex *Exchange) handler() Handler {
+	handler := Handler(ex.roundTrip)
+	for i := len(ex.middlewares) - 1; i >= 0; i-- {
+		handler = ex.middlewares[i](handler)
+	}
+	return handler
+}
+
+// 把网络错误中的请求地址打码, 避免AccessKeyId和Signature随错误信息输出
+func redactError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		if u, parseErr := url.Parse(urlErr.URL); parseErr == nil {
+			urlErr.URL = redactURL(u)
+		}
+	}
+	return err
+}
+
+// 返回查询参数中AccessKeyId和Signature已打码的地址
+func redactURL(u *url.URL) string {
+	redacted := *u
+	query := u.Query()
+	for _, key := range redactedParams {
+		if _, ok := query[key]; ok {
+			query.Set(key, "***")
+		}
+	}
+	redacted.RawQuery = strings.ReplaceAll(query.Encode(), url.QueryEscape("***"), "***")
+	return redacted.String()
+}
@@ -2,15 +2,15 @@ package huobi
 
 import (
 	"encoding/json"
-	"github.com/spf13/cast"
-	"log"
+	"fmt"
+	"github.com/shopspring/decimal"
 )
 
 // 子账户结构
 type SubAccount struct {
-	Currency string `json:"currency"` // 币种
-	Balance  string `json:"balance"`  // 结余
-	Type     string `json:"type"`     // 类型, trade: 交易余额, frozen: 冻结余额
+	Currency string          `json:"currency"` // 币种
+	Balance  decimal.Decimal `json:"balance"`  // 结余
+	Type     string          `json:"type"`     // 类型, trade: 交易余额, frozen: 冻结余额
 }
 
 type Balance struct {
@@ -50,14 +50,14 @@ type CurrencysReturn struct {
 }
 
 type KLineData struct {
-	ID     int64   `json:"id"`     // K线ID
-	Amount float64 `json:"amount"` // 成交量
-	Count  int64   `json:"count"`  // 成交笔数
-	Open   float64 `json:"open"`   // 开盘价
-	Close  float64 `json:"close"`  // 收盘价, 当K线为最晚的一根时, 时最新成交价
-	Low    float64 `json:"low"`    // 最低价
-	High   float64 `json:"high"`   // 最高价
-	Vol    float64 `json:"vol"`    // 成交额, 即SUM(每一笔成交价 * 该笔的成交数量)
+	ID     int64           `json:"id"`     // K线ID
+	Amount decimal.Decimal `json:"amount"` // 成交量
+	Count  int64           `json:"count"`  // 成交笔数
+	Open   decimal.Decimal `json:"open"`   // 开盘价
+	Close  decimal.Decimal `json:"close"`  // 收盘价, 当K线为最晚的一根时, 时最新成交价
+	Low    decimal.Decimal `json:"low"`    // 最低价
+	High   decimal.Decimal `json:"high"`   // 最高价
+	Vol    decimal.Decimal `json:"vol"`    // 成交额, 即SUM(每一笔成交价 * 该笔的成交数量)
 }
 
 type KLineReturn struct {
@@ -70,10 +70,10 @@ type KLineReturn struct {
 }
 
 type MarketDepth struct {
-	ID   int64       `json:"id,omitempty"` // 消息ID
-	Ts   int64       `json:"ts,omitempty"` // 消息声称事件, 单位: 毫秒
-	Bids [][]float64 `json:"bids"`         // 买盘, [price(成交价), amount(成交量)], 按price降序排列
-	Asks [][]float64 `json:"asks"`         // 卖盘, [price(成交价), amount(成交量)], 按price升序排列
+	ID   int64               `json:"id,omitempty"` // 消息ID
+	Ts   int64               `json:"ts,omitempty"` // 消息声称事件, 单位: 毫秒
+	Bids [][]decimal.Decimal `json:"bids"`         // 买盘, [price(成交价), amount(成交量)], 按price降序排列
+	Asks [][]decimal.Decimal `json:"asks"`         // 卖盘, [price(成交价), amount(成交量)], 按price升序排列
 }
 
 type MarketDepthReturn struct {
@@ -86,15 +86,15 @@ type MarketDepthReturn struct {
 }
 
 type MarketDetail struct {
-	ID     int64   `json:"id"`     // 消息ID
-	Ts     int64   `json:"ts"`     // 24小时统计时间
-	Amount float64 `json:"amount"` // 24小时成交量
-	Open   float64 `json:"open"`   // 前24小时成交价
-	Close  float64 `json:"close"`  // 当前成交价
-	High   float64 `json:"high"`   // 近24小时最高价
-	Low    float64 `json:"low"`    // 近24小时最低价
-	Count  int64   `json:"count"`  // 近24小时累计成交数
-	Vol    float64 `json:"vol"`    // 近24小时累计成交额, 即SUM(每一笔成交价 * 该笔的成交量)
+	ID     int64           `json:"id"`     // 消息ID
+	Ts     int64           `json:"ts"`     // 24小时统计时间
+	Amount decimal.Decimal `json:"amount"` // 24小时成交量
+	Open   decimal.Decimal `json:"open"`   // 前24小时成交价
+	Close  decimal.Decimal `json:"close"`  // 当前成交价
+	High   decimal.Decimal `json:"high"`   // 近24小时最高价
+	Low    decimal.Decimal `json:"low"`    // 近24小时最低价
+	Count  int64           `json:"count"`  // 近24小时累计成交数
+	Vol    decimal.Decimal `json:"vol"`    // 近24小时累计成交额, 即SUM(每一笔成交价 * 该笔的成交量)
 }
 
 type MarketDetailReturn struct {
@@ -107,18 +107,23 @@ type MarketDetailReturn struct {
 }
 
 type PlaceRequestParams struct {
-	AccountID string `json:"account-id"` // 账户ID
-	Amount    string `json:"amount"`     // 限价表示下单数量, 市价买单时表示买多少钱, 市价卖单时表示卖多少币
-	Price     string `json:"price"`      // 下单价格, 市价单不传该参数
-	Source    string `json:"source"`     // 订单来源, api: API调用, margin-api: 借贷资产交易
-	Symbol    string `json:"symbol"`     // 交易对, btcusdt, bccbtc......
-	Type      string `json:"type"`       // 订单类型, buy-market: 市价买, sell-market: 市价卖, buy-limit: 限价买, sell-limit: 限价卖
+	AccountID string          `json:"account-id"` // 账户ID
+	Amount    decimal.Decimal `json:"amount"`     // 限价表示下单数量, 市价买单时表示买多少钱, 市价卖单时表示卖多少币
+	Price     decimal.Decimal `json:"price"`      // 下单价格, 市价单为0, 不传该参数
+	Source    string          `json:"source"`     // 订单来源, api: API调用, margin-api: 借贷资产交易
+	Symbol    string          `json:"symbol"`     // 交易对, btcusdt, bccbtc......
+	Type      OrderType       `json:"type"`       // 订单类型, buy-market: 市价买, sell-market: 市价卖, buy-limit: 限价买, sell-limit: 限价卖......
+
+	StopPrice decimal.Decimal `json:"stop-price"`         // 止盈止损单的触发价, 其它订单为0
+	Operator  StopOperator    `json:"operator,omitempty"` // 止盈止损单的触发条件, gte或lte
+
+	ClientOrderID string `json:"client-order-id,omitempty"` // 用户自编订单号, 24小时内唯一, 最长64位
 }
 
 func (place *PlaceRequestParams) String() string {
 	bytes, err := json.Marshal(place)
 	if err != nil {
-		log.Fatalln(place)
+		return fmt.Sprintf("%+v", *place)
 	}
 	return string(bytes)
 }
@@ -130,14 +135,124 @@ type PlaceReturn struct {
 	ErrMsg  string `json:"err-msg"`
 }
 
+// 批量下单中一笔订单的结果, 成功时有OrderID, 失败时有ErrCode
+type BatchPlaceItem struct {
+	OrderID       int64  `json:"order-id"`
+	ClientOrderID string `json:"client-order-id"`
+	ErrCode       string `json:"err-code"`
+	ErrMsg        string `json:"err-msg"`
+}
+
+type BatchPlaceReturn struct {
+	Status  string           `json:"status"`
+	Data    []BatchPlaceItem `json:"data"`
+	ErrCode string           `json:"err-code"`
+	ErrMsg  string           `json:"err-msg"`
+}
+
+// 按条件批量撤销挂单的结果, NextID为-1时表示没有更多符合条件的挂单
+type BatchCancelOpenData struct {
+	SuccessCount int   `json:"success-count"`
+	FailedCount  int   `json:"failed-count"`
+	NextID       int64 `json:"next-id"`
+}
+
+type BatchCancelOpenReturn struct {
+	Status  string              `json:"status"`
+	Data    BatchCancelOpenData `json:"data"`
+	ErrCode string              `json:"err-code"`
+	ErrMsg  string              `json:"err-msg"`
+}
+
+// 按订单ID批量撤单时撤销失败的订单
+type BatchCancelFailed struct {
+	OrderID       string `json:"order-id"`
+	ClientOrderID string `json:"client-order-id"`
+	OrderState    int    `json:"order-state"` // 订单状态, -1表示订单不存在
+	ErrCode       string `json:"err-code"`
+	ErrMsg        string `json:"err-msg"`
+}
+
+type BatchCancelData struct {
+	Success []string            `json:"success"` // 已提交撤单的订单ID或用户自编订单号
+	Failed  []BatchCancelFailed `json:"failed"`
+}
+
+type BatchCancelReturn struct {
+	Status  string          `json:"status"`
+	Data    BatchCancelData `json:"data"`
+	ErrCode string          `json:"err-code"`
+	ErrMsg  string          `json:"err-msg"`
+}
+
 type SymbolsData struct {
-	BaseCurrency          string  `json:"base-currency"`    // 基础币种
-	QuoteCurrency         string  `json:"quote-currency"`   // 计价币种
-	PricePrecision        int     `json:"price-precision"`  // 价格精度位数(0为个位)
-	AmountPrecision       int     `json:"amount-precision"` // 数量精度位数(0为个位)
-	SymbolPartition       string  `json:"symbol-partition"` // 交易区, main: 主区, innovation: 创新区, bifurcation: 分叉区
-	LimitOrderMinOrderAmt float64 `json:"limit-order-min-order-amt"`
-	LimitOrderMaxOrderAmt float64 `json:"limit-order-max-order-amt"`
+	BaseCurrency          string          `json:"base-currency"`    // 基础币种
+	QuoteCurrency         string          `json:"quote-currency"`   // 计价币种
+	PricePrecision        int             `json:"price-precision"`  // 价格精度位数(0为个位)
+	AmountPrecision       int             `json:"amount-precision"` // 数量精度位数(0为个位)
+	SymbolPartition       string          `json:"symbol-partition"` // 交易区, main: 主区, innovation: 创新区, bifurcation: 分叉区
+	LimitOrderMinOrderAmt decimal.Decimal `json:"limit-order-min-order-amt"`
+	LimitOrderMaxOrderAmt decimal.Decimal `json:"limit-order-max-order-amt"`
+
+	Symbol                   string          `json:"symbol"`                      // 交易对, 如 btcusdt
+	State                    string          `json:"state"`                       // 状态, online: 已上线, offline: 已下线, suspend: 暂停交易, pre-online: 即将上线
+	ValuePrecision           int             `json:"value-precision"`             // 成交金额精度位数
+	MinOrderAmt              decimal.Decimal `json:"min-order-amt"`               // 最小下单量(已废弃, 以limit-order-min-order-amt为准)
+	MaxOrderAmt              decimal.Decimal `json:"max-order-amt"`               // 最大下单量(已废弃, 以limit-order-max-order-amt为准)
+	MinOrderValue            decimal.Decimal `json:"min-order-value"`             // 最小下单金额
+	SellMarketMinOrderAmt    decimal.Decimal `json:"sell-market-min-order-amt"`   // 市价卖单最小下单量
+	SellMarketMaxOrderAmt    decimal.Decimal `json:"sell-market-max-order-amt"`   // 市价卖单最大下单量
+	BuyMarketMaxOrderValue   decimal.Decimal `json:"buy-market-max-order-value"`  // 市价买单最大下单金额
+	MaxOrderValue            decimal.Decimal `json:"max-order-value"`             // 最大下单金额, 仅部分交易对有
+	LeverageRatio            decimal.Decimal `json:"leverage-ratio"`              // 逐仓杠杆最大倍数
+	SuperMarginLeverageRatio decimal.Decimal `json:"super-margin-leverage-ratio"` // 全仓杠杆最大倍数
+	FundingLeverageRatio     decimal.Decimal `json:"funding-leverage-ratio"`      // C2C杠杆最大倍数
+	ApiTrading               string          `json:"api-trading"`                 // API交易, enabled: 允许, disabled: 禁止
+	Tags                     string          `json:"tags"`                        // 标签, 多个以逗号分隔, 如 etp,holdinglimit
+	Underlying               string          `json:"underlying"`                  // ETP标的交易对
+	MgmtFeeRate              decimal.Decimal `json:"mgmt-fee-rate"`               // ETP持仓管理费率
+	ChargeTime               string          `json:"charge-time"`                 // ETP持仓管理费收取时间
+	RebalTime                string          `json:"rebal-time"`                  // ETP定期调仓时间
+	RebalThreshold           decimal.Decimal `json:"rebal-threshold"`             // ETP临时调仓阈值
+	InitNav                  decimal.Decimal `json:"init-nav"`                    // ETP初始净值
+}
+
+// 交易对名称, 接口未返回symbol时由基础币种和计价币种拼接
+func (symbol *SymbolsData) Name() string {
+	if symbol.Symbol != "" {
+		return symbol.Symbol
+	}
+	return symbol.BaseCurrency + symbol.QuoteCurrency
+}
+
+// 是否可以通过API交易, 接口未返回state或api-trading时视为可以
+func (symbol *SymbolsData) IsTradable() bool {
+	return (symbol.State == "" || symbol.State == "online") && symbol.ApiTrading != "disabled"
+}
+
+// 按价格精度四舍五入
+func (symbol *SymbolsData) TruncPrice(price decimal.Decimal) decimal.Decimal {
+	return price.Round(int32(symbol.PricePrecision))
+}
+
+// 按数量精度截断, 不会超过可用余额
+func (symbol *SymbolsData) TruncAmount(amount decimal.Decimal) decimal.Decimal {
+	return amount.Truncate(int32(symbol.AmountPrecision))
+}
+
+// 按价格精度格式化, 如精度为2时 1.005 -> "1.01"
+func (symbol *SymbolsData) FormatPrice(price decimal.Decimal) string {
+	return price.StringFixed(int32(symbol.PricePrecision))
+}
+
+// 按数量精度格式化, 多余的位数直接截断
+func (symbol *SymbolsData) FormatAmount(amount decimal.Decimal) string {
+	return symbol.TruncAmount(amount).StringFixed(int32(symbol.AmountPrecision))
+}
+
+// 按金额精度格式化, 多余的位数直接截断, 用于市价买单的amount
+func (symbol *SymbolsData) FormatValue(value decimal.Decimal) string {
+	return value.Truncate(int32(symbol.ValuePrecision)).StringFixed(int32(symbol.ValuePrecision))
 }
 
 type SymbolsReturn struct {
@@ -147,17 +262,58 @@ type SymbolsReturn struct {
 	ErrMsg  string         `json:"err-msg"`
 }
 
+// /v2/settings/common/symbols返回的交易对
+type SymbolV2 struct {
+	Symbol                   string          `json:"sc"`                  // 交易对, 如 btcusdt
+	DisplayName              string          `json:"dn"`                  // 交易对显示名称
+	BaseCurrency             string          `json:"bc"`                  // 基础币种
+	BaseCurrencyDisplayName  string          `json:"bcdn"`                // 基础币种显示名称
+	QuoteCurrency            string          `json:"qc"`                  // 计价币种
+	QuoteCurrencyDisplayName string          `json:"qcdn"`                // 计价币种显示名称
+	State                    string          `json:"state"`               // 状态, online, offline, suspend, pre-online
+	WhiteEnabled             bool            `json:"whe"`                 // 是否白名单交易对
+	CountryDisabled          bool            `json:"cd"`                  // 是否对部分国家禁用
+	TradeEnabled             bool            `json:"te"`                  // 是否可交易
+	TradeOpenAt              int64           `json:"toa"`                 // 开始交易时间, 单位毫秒
+	SymbolPartition          string          `json:"sp"`                  // 交易区
+	Weight                   int             `json:"w"`                   // 排序权重
+	TradeTotalPrecision      int             `json:"ttp"`                 // 成交金额精度位数
+	TradeAmountPrecision     int             `json:"tap"`                 // 数量精度位数
+	TradePricePrecision      int             `json:"tpp"`                 // 价格精度位数
+	FeePrecision             int             `json:"fp"`                  // 手续费精度位数
+	SuspendDesc              string          `json:"suspend_desc"`        // 暂停交易说明
+	TransferBoardDesc        string          `json:"transfer_board_desc"` // 转板说明
+	Tags                     string          `json:"tags"`                // 标签
+	LeverageRatio            decimal.Decimal `json:"lr"`                  // 逐仓杠杆最大倍数
+	SuperMarginLeverageRatio decimal.Decimal `json:"smlr"`                // 全仓杠杆最大倍数
+	FundingLeverageRatio     decimal.Decimal `json:"flr"`                 // C2C杠杆最大倍数
+	Direction                int             `json:"d"`                   // ETP方向, 1: 多, 2: 空
+	EtpLeverageRatio         decimal.Decimal `json:"elr"`                 // ETP杠杆倍数
+	CastState                string          `json:"castate"`             // 集合竞价状态
+	CallAuction1OpenAt       int64           `json:"ca1oa"`               // 集合竞价第一阶段开始时间
+	CallAuction2OpenAt       int64           `json:"ca2oa"`               // 集合竞价第二阶段开始时间
+}
+
+type SymbolsV2Return struct {
+	Status  string      `json:"status"`
+	Ts      string      `json:"ts"`
+	Full    int         `json:"full"` // 1: 全量数据, 0: 增量数据
+	Data    []*SymbolV2 `json:"data"`
+	ErrCode string      `json:"err-code"`
+	ErrMsg  string      `json:"err-msg"`
+}
+
 type Ticker struct {
-	ID     int64     `json:"id"`     // K线ID
-	Amount float64   `json:"amount"` // 成交量
-	Count  int64     `json:"count"`  // 成交笔数
-	Open   float64   `json:"open"`   // 开盘价
-	Close  float64   `json:"close"`  // 收盘价
-	Low    float64   `json:"low"`    // 最低价
-	High   float64   `json:"high"`   // 最高价
-	Vol    float64   `json:"vol"`    // 成交额
-	Bid    []float64 `json:"bid"`    // [买1价, 买1量]
-	Ask    []float64 `json:"ask"`    // [卖1价, 卖1量]
+	ID     int64             `json:"id"`     // K线ID
+	Amount decimal.Decimal   `json:"amount"` // 成交量
+	Count  int64             `json:"count"`  // 成交笔数
+	Open   decimal.Decimal   `json:"open"`   // 开盘价
+	Close  decimal.Decimal   `json:"close"`  // 收盘价
+	Low    decimal.Decimal   `json:"low"`    // 最低价
+	High   decimal.Decimal   `json:"high"`   // 最高价
+	Vol    decimal.Decimal   `json:"vol"`    // 成交额
+	Bid    []decimal.Decimal `json:"bid"`    // [买1价, 买1量]
+	Ask    []decimal.Decimal `json:"ask"`    // [卖1价, 卖1量]
 }
 
 type TickerReturn struct {
@@ -169,15 +325,15 @@ type TickerReturn struct {
 	ErrMsg  string `json:"err-msg"`
 }
 
-func (ticker *TickerReturn) GetPrice() float64 {
+func (ticker *TickerReturn) GetPrice() decimal.Decimal {
 	return ticker.Tick.Close
 }
 
-func (ticker *TickerReturn) GetBuyPrice() float64 {
+func (ticker *TickerReturn) GetBuyPrice() decimal.Decimal {
 	return ticker.Tick.Bid[0]
 }
 
-func (ticker *TickerReturn) GetSellPrice() float64 {
+func (ticker *TickerReturn) GetSellPrice() decimal.Decimal {
 	return ticker.Tick.Ask[0]
 }
 
@@ -189,11 +345,11 @@ type TimestampReturn struct {
 }
 
 type TradeData struct {
-	ID        int64   `json:"id"`        //成交ID
-	Price     float64 `json:"price"`     // 成交价
-	Amount    float64 `json:"amount"`    // 成交量
-	Direction string  `json:"direction"` // 主动成交方向
-	Ts        int64   `json:"ts"`        // 成交时间
+	ID        int64           `json:"id"`        //成交ID
+	Price     decimal.Decimal `json:"price"`     // 成交价
+	Amount    decimal.Decimal `json:"amount"`    // 成交量
+	Direction string          `json:"direction"` // 主动成交方向
+	Ts        int64           `json:"ts"`        // 成交时间
 }
 
 type TradeTick struct {
@@ -212,11 +368,11 @@ type TradeReturn struct {
 }
 
 type TradeDetailData struct {
-	ID        int64   `json:"id"`        // 成交ID
-	Price     float64 `json:"price"`     // 成交价
-	Amount    float64 `json:"amount"`    // 成交量
-	Direction string  `json:"direction"` // 主动成交方向
-	Ts        int64   `json:"ts"`        // 成交时间
+	ID        int64           `json:"id"`        // 成交ID
+	Price     decimal.Decimal `json:"price"`     // 成交价
+	Amount    decimal.Decimal `json:"amount"`    // 成交量
+	Direction string          `json:"direction"` // 主动成交方向
+	Ts        int64           `json:"ts"`        // 成交时间
 }
 
 type TradeDetail struct {
@@ -238,34 +394,40 @@ type Order struct {
 	ID               int64 `json:"id"`
 	Symbol           string
 	AccountId        int64 `json:"account-id"`
-	Amount           string
-	Price            string
-	CreateAt         int64
-	Type             string
-	FilledAmount     string `json:"field-amount"`
-	FilledCashAmount string `json:"field-cash-amount"`
+	Amount           decimal.Decimal
+	Price            decimal.Decimal
+	CreateAt         int64 `json:"created-at"`
+	FinishedAt       int64 `json:"finished-at"`
+	CanceledAt       int64 `json:"canceled-at"`
+	Type             OrderType
+	FilledAmount     decimal.Decimal `json:"field-amount"`
+	FilledCashAmount decimal.Decimal `json:"field-cash-amount"`
+	FilledFees       decimal.Decimal `json:"field-fees"`
 	Source           string
 	State            string
+	ClientOrderID    string          `json:"client-order-id"`
+	StopPrice        decimal.Decimal `json:"stop-price"`
+	Operator         StopOperator    `json:"operator"`
 }
 
-func (order *Order) GetFilledAmount() float64 {
-	return cast.ToFloat64(order.FilledAmount)
+func (order *Order) GetFilledAmount() decimal.Decimal {
+	return order.FilledAmount
 }
 
-func (order *Order) GetUnFilledAmount() float64 {
-	return cast.ToFloat64(order.Amount) - cast.ToFloat64(order.FilledAmount)
+func (order *Order) GetUnFilledAmount() decimal.Decimal {
+	return order.Amount.Sub(order.FilledAmount)
 }
 
-func (order *Order) GetAmount() float64 {
-	return cast.ToFloat64(order.Amount)
+func (order *Order) GetAmount() decimal.Decimal {
+	return order.Amount
 }
 
-func (order *Order) GetPrice() float64 {
-	return cast.ToFloat64(order.Price)
+func (order *Order) GetPrice() decimal.Decimal {
+	return order.Price
 }
 
 func (order *Order) String() string {
-	return order.Type + " amount:" + order.Amount + " price:" + order.Price
+	return string(order.Type) + " amount:" + order.Amount.String() + " price:" + order.Price.String()
 }
 
 func (order *Order) IsFilled() bool {
@@ -277,6 +439,58 @@ type OrderReturn struct {
 	Data   []Order `json:"data"`
 }
 
+// 成交角色
+const (
+	RoleMaker = "maker"
+	RoleTaker = "taker"
+)
+
+// 一笔成交明细, 字段与/v1/order/matchresults的响应一致
+type MatchResult struct {
+	ID                int64           `json:"id"`                  // 成交记录ID, 用于翻页
+	Symbol            string          `json:"symbol"`              // 交易对
+	OrderID           int64           `json:"order-id"`            // 订单ID
+	MatchID           int64           `json:"match-id"`            // 撮合ID
+	TradeID           int64           `json:"trade-id"`            // 成交ID, 与行情中的成交ID一致
+	Type              OrderType       `json:"type"`                // 订单类型
+	Source            string          `json:"source"`              // 订单来源
+	Price             decimal.Decimal `json:"price"`               // 成交价
+	FilledAmount      decimal.Decimal `json:"filled-amount"`       // 成交数量
+	FilledFees        decimal.Decimal `json:"filled-fees"`         // 手续费, 以FeeCurrency计
+	FeeCurrency       string          `json:"fee-currency"`        // 手续费币种, 买单为基础币种, 卖单为计价币种
+	FilledPoints      decimal.Decimal `json:"filled-points"`       // 抵扣的手续费, 以FeeDeductCurrency计
+	FeeDeductCurrency string          `json:"fee-deduct-currency"` // 抵扣手续费的币种, 如ht, hbpoint, 未抵扣时为空
+	FeeDeductState    string          `json:"fee-deduct-state"`    // 抵扣状态, ongoing或done
+	Role              string          `json:"role"`                // maker或taker
+	CreatedAt         int64           `json:"created-at"`          // 成交时间
+}
+
+func (result *MatchResult) IsMaker() bool {
+	return result.Role == RoleMaker
+}
+
+// 成交金额
+func (result *MatchResult) GetValue() decimal.Decimal {
+	return result.Price.Mul(result.FilledAmount)
+}
+
+// 是否用HT或点卡抵扣了手续费
+func (result *MatchResult) IsFeeDeducted() bool {
+	return result.FeeDeductCurrency != "" && result.FilledPoints.IsPositive()
+}
+
+type MatchResultsReturn struct {
+	Status string        `json:"status"`
+	Data   []MatchResult `json:"data"`
+}
+
+// /v1/order/history的响应, NextTime为0时没有更多订单
+type OrderHistoryReturn struct {
+	Status   string  `json:"status"`
+	Data     []Order `json:"data"`
+	NextTime int64   `json:"next-time"`
+}
+
 type OrderReturnSingle struct {
 	Status string `json:"status"`
 	Data   Order  `json:"data"`
@@ -294,9 +508,9 @@ type ContractSubAccount struct {
 
 type ContractSymbolAccount struct {
 	Symbol           string
-	MarginBalance    float64 `json:"margin_balance"`
-	LiquidationPrice float64 `json:"liquidation_price"`
-	RiskRate         float64 `json:"risk_rate"`
+	MarginBalance    decimal.Decimal `json:"margin_balance"`
+	LiquidationPrice decimal.Decimal `json:"liquidation_price"`
+	RiskRate         decimal.Decimal `json:"risk_rate"`
 }
 
 type ContractAggregate struct {
@@ -0,0 +1,231 @@
+package huobi
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/spf13/cast"
+)
+
+// 一组API地址, 每个Exchange持有自己的一份, 互不影响
+type Endpoints struct {
+	MarketURL    string // 行情API地址, 如 https://api.huobi.pro
+	TradeURL     string // 现货交易API地址
+	HostName     string // 现货交易签名使用的主机名
+	ContractURL  string // 合约API地址, 如 https://api.hbdm.com
+	HostContract string // 合约签名使用的主机名
+}
+
+// 以当前包级变量MARKET_URL等构造默认地址
+func DefaultEndpoints() Endpoints {
+	return Endpoints{
+		MarketURL:    MARKET_URL,
+		TradeURL:     TRADE_URL,
+		HostName:     HOST_NAME,
+		ContractURL:  CONTRACT_URL,
+		HostContract: HOST_CONTRACT,
+	}
+}
+
+// Exchange的构造选项
+type Option func(ex *Exchange)
+
+// 替换整组API地址
+func WithEndpoints(endpoints Endpoints) Option {
+	return func(ex *Exchange) {
+		ex.endpoints = endpoints
+	}
+}
+
+// 设置现货行情与交易地址, host为签名使用的主机名
+// 例: WithSpotEndpoint("https://api-aws.huobi.pro", "api-aws.huobi.pro")
+func WithSpotEndpoint(baseURL, host string) Option {
+	return func(ex *Exchange) {
+		ex.endpoints.MarketURL = baseURL
+		ex.endpoints.TradeURL = baseURL
+		ex.endpoints.HostName = host
+	}
+}
+
+// 单独设置行情地址
+func WithMarketEndpoint(baseURL string) Option {
+	return func(ex *Exchange) {
+		ex.endpoints.MarketURL = baseURL
+	}
+}
+
+// 设置合约地址, host为签名使用的主机名
+func WithContractEndpoint(baseURL, host string) Option {
+	return func(ex *Exchange) {
+		ex.endpoints.ContractURL = baseURL
+		ex.endpoints.HostContract = host
+	}
+}
+
+// 使用调用方提供的HTTP客户端, 超时、代理、TLS等均由该客户端决定
+func WithHttpClient(httpClient *http.Client) Option {
+	return func(ex *Exchange) {
+		ex.httpClient = httpClient
+	}
+}
+
+// 使用调用方提供的Transport, 超时为DefaultTimeout
+func WithTransport(transport http.RoundTripper) Option {
+	return func(ex *Exchange) {
+		ex.httpClient = &http.Client{
+			Timeout:   ex.client().Timeout,
+			Transport: transport,
+		}
+	}
+}
+
+// 设置单次请求的超时时间
+func WithTimeout(timeout time.Duration) Option {
+	return func(ex *Exchange) {
+		httpClient := *ex.client()
+		httpClient.Timeout = timeout
+		ex.httpClient = &httpClient
+	}
+}
+
+// 通过指定代理发出请求, 如 http://127.0.0.1:1087
+func WithProxy(proxyURL *url.URL) Option {
+	return func(ex *Exchange) {
+		ex.ownTransport().Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// 使用自定义的TLS配置
+func WithTLSConfig(config *tls.Config) Option {
+	return func(ex *Exchange) {
+		ex.ownTransport().TLSClientConfig = config
+	}
+}
+
+// 按选项构造Exchange, 与NewExchange一样会加载交易对和现货账户ID
+// 加载失败时不返回错误, 之后的签名请求都返回该错误, 可通过InitErr查看
+// 需要在构造时检查错误的请使用OpenExchange
+func NewExchangeWithOptions(ak, sk string, opts ...Option) *Exchange {
+	ex := newExchange(ak, sk, opts...)
+	if err := ex.init(); err != nil {
+		ex.log().Error("init exchange error", "error", err)
+		ex.initErr = err
+	}
+	return ex
+}
+
+// NewExchange或NewExchangeWithOptions加载交易对和现货账户ID时的错误, 成功时为nil
+func (ex *Exchange) InitErr() error {
+	return ex.initErr
+}
+
+// 按选项构造Exchange, 加载交易对或现货账户失败时返回错误
+func OpenExchange(ak, sk string, opts ...Option) (*Exchange, error) {
+	ex := newExchange(ak, sk, opts...)
+	if err := ex.init(); err != nil {
+		ex.Close()
+		return nil, err
+	}
+	return ex, nil
+}
+
+func newExchange(ak, sk string, opts ...Option) *Exchange {
+	ex := &Exchange{
+		name:        "huobi",
+		endpoints:   DefaultEndpoints(),
+		clock:       &clock{},
+		symbols:     newSymbolRegistry(),
+		credentials: NewStaticCredentials(ak, sk),
+	}
+	for _, opt := range opts {
+		opt(ex)
+	}
+	return ex
+}
+
+// 对时并加载交易对和现货账户ID
+func (ex *Exchange) init() error {
+	if ex.clock.interval > 0 {
+		if _, err := ex.SyncTime(); err != nil {
+			return err
+		}
+		ex.startClockSync()
+	}
+
+	if err := ex.RefreshSymbols(); err != nil {
+		return err
+	}
+	ex.startSymbolRefresh()
+
+	accounts, err := ex.GetAccounts()
+	if err != nil {
+		return err
+	}
+	for _, data := range accounts.Data {
+		if data.Type == `spot` {
+			ex.accountId = cast.ToString(data.ID)
+		}
+	}
+	if ex.accountId == "" {
+		return ErrNoSpotAccount
+	}
+	return nil
+}
+
+// 停止Exchange的后台任务, 如定时对时和刷新交易对
+func (ex *Exchange) Close() {
+	ex.clock.close()
+	ex.symbols.close()
+}
+
+// 包级行情函数使用的Exchange, 地址取自当前的包级变量
+func defaultExchange() *Exchange {
+	return newExchange("", "")
+}
+
+// 返回Exchange当前使用的API地址
+func (ex *Exchange) Endpoints() Endpoints {
+	return ex.endpoints
+}
+
+// 返回Exchange独占的Transport, 修改它不会影响DefaultHttpClient或调用方传入的客户端
+func (ex *Exchange) ownTransport() *http.Transport {
+	httpClient := *ex.client()
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if ok {
+		transport = transport.Clone()
+	} else {
+		transport = NewTransport()
+	}
+	httpClient.Transport = transport
+	ex.httpClient = &httpClient
+	return transport
+}
+
+// 返回绑定了ctx的Exchange副本, 通过副本发出的所有请求随ctx取消或超时
+// 例: ex.WithContext(ctx).GetOrder(orderId)
+func (ex *Exchange) WithContext(ctx context.Context) *Exchange {
+	if ctx == nil {
+		panic("nil context")
+	}
+	ex2 := *ex
+	ex2.ctx = ctx
+	return &ex2
+}
+
+// 返回Exchange绑定的ctx, 未绑定时为context.Background()
+func (ex *Exchange) Context() context.Context {
+	if ex.ctx != nil {
+		return ex.ctx
+	}
+	return context.Background()
+}
+
+// 返回绑定了ctx的默认Exchange, 用于带ctx调用包级行情函数
+// 例: huobi.WithContext(ctx).GetTicker("btcusdt")
+func WithContext(ctx context.Context) *Exchange {
+	return defaultExchange().WithContext(ctx)
+}
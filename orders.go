@@ -0,0 +1,260 @@
+package huobi
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 订单状态
+const (
+	OrderStateCreated         = "created"          // 止盈止损单未触发
+	OrderStateSubmitted       = "submitted"        // 已挂单
+	OrderStatePartialFilled   = "partial-filled"   // 部分成交
+	OrderStateFilled          = "filled"           // 完全成交
+	OrderStatePartialCanceled = "partial-canceled" // 部分成交后撤销
+	OrderStateCanceling       = "canceling"        // 撤销中
+	OrderStateCanceled        = "canceled"         // 已撤销
+)
+
+// 翻页方向
+type PageDirection string
+
+const (
+	PageNext PageDirection = "next" // 从新到旧
+	PagePrev PageDirection = "prev" // 从旧到新
+)
+
+// 每页最多的订单数
+const (
+	SearchOrdersLimit = 100
+	OrderHistoryLimit = 1000
+)
+
+// /v1/order/orders的查询条件, 零值表示不限
+// 时间范围最长48小时, 且在最近180天内, 不设置时由服务端默认为最近48小时
+type OrderSearch struct {
+	Symbol    string        // 交易对, 必填
+	Side      OrderSide     // 买卖方向, 未设置Types时按方向选择订单类型
+	Types     []OrderType   // 订单类型
+	States    []string      // 订单状态, 为空时查询所有已成交和已撤销的订单
+	StartTime time.Time     // 按创建时间查询的开始时间
+	EndTime   time.Time     // 按创建时间查询的结束时间
+	Direct    PageDirection // 翻页方向, 默认PageNext
+	Size      int           // 每页的订单数, 最大100, 默认100
+}
+
+// /v1/order/history的查询条件, 只能查询最近48小时内结束的订单
+type OrderHistorySearch struct {
+	Symbol    string        // 交易对, 为空时查询所有交易对
+	StartTime time.Time     // 开始时间
+	EndTime   time.Time     // 结束时间
+	Direct    PageDirection // 翻页方向, 默认PageNext
+	Size      int           // 每页的订单数, 10到1000, 默认100
+}
+
+// 逐笔遍历订单, 一页取完后自动请求下一页
+//
+//	it := ex.SearchOrders(search)
+//	for it.Next() {
+//		order := it.Order()
+//	}
+//	if err := it.Err(); err != nil {
+//	}
+type OrderIterator struct {
+	pager
+	page []Order
+}
+
+// fetch请求下一页, 返回该页订单和是否还有下一页
+func newOrderIterator(fetch func() ([]Order, bool, error)) *OrderIterator {
+	it := &OrderIterator{}
+	it.pager = newPager(func() (int, bool, error) {
+		page, more, err := fetch()
+		it.page = page
+		return len(page), more, err
+	})
+	return it
+}
+
+// 移动到下一笔订单, 没有更多订单或出错时返回false
+func (it *OrderIterator) Next() bool {
+	return it.next()
+}
+
+// 当前订单
+func (it *OrderIterator) Order() *Order {
+	if i := it.current(); i >= 0 {
+		return &it.page[i]
+	}
+	return nil
+}
+
+// 遍历中遇到的错误
+func (it *OrderIterator) Err() error {
+	return it.err
+}
+
+// 取出剩余的所有订单, 出错时返回已取得的订单和错误
+func (it *OrderIterator) All() ([]Order, error) {
+	var orders []Order
+	for it.Next() {
+		orders = append(orders, *it.Order())
+	}
+	return orders, it.Err()
+}
+
+// 按条件查询订单, 通过from和size自动翻页
+func (ex *Exchange) SearchOrders(search *OrderSearch) *OrderIterator {
+	params := search.params()
+	size := search.Size
+	if size <= 0 || size > SearchOrdersLimit {
+		size = SearchOrdersLimit
+	}
+	params["size"] = strconv.Itoa(size)
+
+	cursor := &fromCursor{size: size}
+	return newOrderIterator(func() ([]Order, bool, error) {
+		cursor.apply(params)
+		orderReturn, err := ex.QueryOrders(params)
+		if err != nil {
+			return nil, false, err
+		}
+		orders := orderReturn.Data
+		ids := make([]int64, len(orders))
+		for i := range orders {
+			ids[i] = orders[i].ID
+		}
+		skip, more := cursor.advance(ids)
+		return orders[skip:], more, nil
+	})
+}
+
+// 请求一页/v1/order/orders, params为原始请求参数
+func (ex *Exchange) QueryOrders(params map[string]string) (*OrderReturn, error) {
+	strRequest := "/v1/order/orders"
+	str, err := ex.ApiKeyGet(params, strRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	orderReturn := &OrderReturn{}
+	err = json.Unmarshal([]byte(str), orderReturn)
+	if err != nil {
+		return nil, err
+	}
+	return orderReturn, nil
+}
+
+func (search *OrderSearch) params() map[string]string {
+	params := make(map[string]string)
+	params["symbol"] = search.Symbol
+	types := search.Types
+	if len(types) == 0 && search.Side != "" {
+		for orderType := range orderTypes {
+			if orderType.Side() == search.Side {
+				types = append(types, orderType)
+			}
+		}
+	}
+	if len(types) > 0 {
+		names := make([]string, 0, len(types))
+		for _, orderType := range types {
+			names = append(names, string(orderType))
+		}
+		sort.Strings(names)
+		params["types"] = strings.Join(names, ",")
+	}
+	states := search.States
+	if len(states) == 0 {
+		states = []string{OrderStateFilled, OrderStatePartialCanceled, OrderStateCanceled}
+	}
+	params["states"] = strings.Join(states, ",")
+	if !search.StartTime.IsZero() {
+		params["start-time"] = strconv.FormatInt(unixMillis(search.StartTime), 10)
+	}
+	if !search.EndTime.IsZero() {
+		params["end-time"] = strconv.FormatInt(unixMillis(search.EndTime), 10)
+	}
+	if search.Direct != "" {
+		params["direct"] = string(search.Direct)
+	}
+	return params
+}
+
+// 查询最近48小时内结束的订单, 通过next-time自动翻页
+func (ex *Exchange) OrderHistory(search *OrderHistorySearch) *OrderIterator {
+	params := make(map[string]string)
+	if search.Symbol != "" {
+		params["symbol"] = search.Symbol
+	}
+	if !search.StartTime.IsZero() {
+		params["start-time"] = strconv.FormatInt(unixMillis(search.StartTime), 10)
+	}
+	if !search.EndTime.IsZero() {
+		params["end-time"] = strconv.FormatInt(unixMillis(search.EndTime), 10)
+	}
+	direct := search.Direct
+	if direct == "" {
+		direct = PageNext
+	}
+	params["direct"] = string(direct)
+	if search.Size > 0 {
+		size := search.Size
+		if size > OrderHistoryLimit {
+			size = OrderHistoryLimit
+		}
+		params["size"] = strconv.Itoa(size)
+	}
+
+	// 上一页的订单ID, 同一时间结束的订单可能同时出现在相邻两页
+	var last map[int64]bool
+	return newOrderIterator(func() ([]Order, bool, error) {
+		historyReturn, err := ex.QueryOrderHistory(params)
+		if err != nil {
+			return nil, false, err
+		}
+		orders := make([]Order, 0, len(historyReturn.Data))
+		for _, order := range historyReturn.Data {
+			if !last[order.ID] {
+				orders = append(orders, order)
+			}
+		}
+		last = make(map[int64]bool, len(historyReturn.Data))
+		for _, order := range historyReturn.Data {
+			last[order.ID] = true
+		}
+		// next-time为下一页的开始时间(prev)或结束时间(next), 没有新订单时停止避免重复请求同一页
+		if historyReturn.NextTime == 0 || len(orders) == 0 {
+			return orders, false, nil
+		}
+		if direct == PagePrev {
+			params["start-time"] = strconv.FormatInt(historyReturn.NextTime, 10)
+		} else {
+			params["end-time"] = strconv.FormatInt(historyReturn.NextTime, 10)
+		}
+		return orders, true, nil
+	})
+}
+
+// 请求一页/v1/order/history, params为原始请求参数
+func (ex *Exchange) QueryOrderHistory(params map[string]string) (*OrderHistoryReturn, error) {
+	strRequest := "/v1/order/history"
+	str, err := ex.ApiKeyGet(params, strRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	historyReturn := &OrderHistoryReturn{}
+	err = json.Unmarshal([]byte(str), historyReturn)
+	if err != nil {
+		return nil, err
+	}
+	return historyReturn, nil
+}
+
+func unixMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,183 @@
+package huobi_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/monkeybang/huobi"
+	"github.com/monkeybang/huobi/huobitest"
+	"github.com/shopspring/decimal"
+)
+
+// 下n笔btcusdt限价单, 奇数笔为卖单; 前filled笔全部成交, 其余卖单撤销
+// 返回已结束订单的ID, 从大到小排列
+func placeFinishedOrders(t *testing.T, server *huobitest.Server, ex *huobi.Exchange, n, filled int) []int64 {
+	t.Helper()
+	d := decimal.RequireFromString
+	for i := 0; i < n; i++ {
+		side := huobi.SideBuy
+		if i%2 == 1 {
+			side = huobi.SideSell
+		}
+		if _, err := ex.PlaceBuilt(huobi.NewOrderBuilder("btcusdt", side).Limit(d("1"), d("100"))); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := 1; i <= filled; i++ {
+		server.FillOrder(int64(i), d("1"))
+	}
+	if _, err := ex.BatchCancelOpenOrders(&huobi.CancelOpenOrdersFilter{Side: huobi.SideSell}); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int64
+	for _, order := range server.Orders() {
+		if order.State == huobitest.OrderStateFilled || order.State == huobitest.OrderStateCanceled {
+			ids = append(ids, order.ID)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] > ids[j] })
+	return ids
+}
+
+// 检查遍历结果与want一致, 没有重复和遗漏
+func checkOrderIDs(t *testing.T, orders []huobi.Order, err error, want []int64) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	seen := make(map[int64]bool)
+	for _, order := range orders {
+		if seen[order.ID] {
+			t.Fatalf("order %d returned twice", order.ID)
+		}
+		seen[order.ID] = true
+	}
+	if len(orders) != len(want) {
+		t.Fatalf("got %d orders, want %d", len(orders), len(want))
+	}
+	for i, order := range orders {
+		if order.ID != want[i] {
+			t.Fatalf("order %d = %d, want %d", i, order.ID, want[i])
+		}
+	}
+}
+
+func reversed(ids []int64) []int64 {
+	reversed := make([]int64, len(ids))
+	for i, id := range ids {
+		reversed[len(ids)-1-i] = id
+	}
+	return reversed
+}
+
+func TestSearchOrdersPaging(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+	want := placeFinishedOrders(t, server, ex, 250, 40)
+
+	orders, err := ex.SearchOrders(&huobi.OrderSearch{Symbol: "btcusdt", Size: 30}).All()
+	checkOrderIDs(t, orders, err, want)
+
+	orders, err = ex.SearchOrders(&huobi.OrderSearch{Symbol: "btcusdt", Direct: huobi.PagePrev, Size: 7}).All()
+	checkOrderIDs(t, orders, err, reversed(want))
+
+	// 页大小正好整除结果数时, 最后一次请求返回空页
+	orders, err = ex.SearchOrders(&huobi.OrderSearch{Symbol: "btcusdt", Size: len(want) / 5}).All()
+	checkOrderIDs(t, orders, err, want)
+}
+
+// 模拟from包含在结果中的服务端: 下一页的第一笔是上一页的最后一笔
+func TestSearchOrdersInclusiveFrom(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+	want := placeFinishedOrders(t, server, ex, 100, 20)
+
+	server.Handle(http.MethodGet, "/v1/order/orders", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		size, _ := strconv.Atoi(query.Get("size"))
+		from, _ := strconv.ParseInt(query.Get("from"), 10, 64)
+		orders := make([]huobitest.Order, 0)
+		all := server.Orders()
+		for i := len(all) - 1; i >= 0 && len(orders) < size; i-- {
+			order := all[i]
+			if order.State == huobitest.OrderStateSubmitted || (from != 0 && order.ID > from) {
+				continue
+			}
+			orders = append(orders, order)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "data": orders})
+	})
+
+	orders, err := ex.SearchOrders(&huobi.OrderSearch{Symbol: "btcusdt", Size: 9}).All()
+	checkOrderIDs(t, orders, err, want)
+}
+
+func TestOrderHistoryPaging(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+	placeFinishedOrders(t, server, ex, 120, 30)
+
+	// 模拟服务按结束时间排序, 结束时间各不相同
+	var want []int64
+	orders := server.Orders()
+	sort.Slice(orders, func(i, j int) bool { return orders[i].FinishedAt > orders[j].FinishedAt })
+	for _, order := range orders {
+		if order.FinishedAt != 0 {
+			want = append(want, order.ID)
+		}
+	}
+
+	history, err := ex.OrderHistory(&huobi.OrderHistorySearch{Size: 20}).All()
+	checkOrderIDs(t, history, err, want)
+
+	history, err = ex.OrderHistory(&huobi.OrderHistorySearch{Symbol: "btcusdt", Direct: huobi.PagePrev, Size: 13}).All()
+	checkOrderIDs(t, history, err, reversed(want))
+}
+
+// 模拟多笔订单在同一毫秒结束, next-time等于上一页最后一笔的结束时间, 相邻两页有重叠
+func TestOrderHistorySharedNextTime(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	ex := openExchange(t, server)
+	defer ex.Close()
+
+	// 40笔订单, 每3笔共用一个结束时间, 从新到旧排列
+	var all []huobitest.Order
+	var want []int64
+	for i := 0; i < 40; i++ {
+		id := int64(1000 - i)
+		all = append(all, huobitest.Order{ID: id, Symbol: "btcusdt", State: huobitest.OrderStateFilled, FinishedAt: int64(500 - i/3)})
+		want = append(want, id)
+	}
+	server.Handle(http.MethodGet, "/v1/order/history", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		size, _ := strconv.Atoi(query.Get("size"))
+		endTime, _ := strconv.ParseInt(query.Get("end-time"), 10, 64)
+		orders := make([]huobitest.Order, 0)
+		for _, order := range all {
+			if endTime != 0 && order.FinishedAt > endTime {
+				continue
+			}
+			orders = append(orders, order)
+		}
+		var nextTime int64
+		if len(orders) > size {
+			orders = orders[:size]
+			nextTime = orders[size-1].FinishedAt
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "ok", "data": orders, "next-time": nextTime})
+	})
+
+	history, err := ex.OrderHistory(&huobi.OrderHistorySearch{Size: 5}).All()
+	checkOrderIDs(t, history, err, want)
+}
@@ -0,0 +1,218 @@
+package huobi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// 买卖方向
+type OrderSide string
+
+const (
+	SideBuy  OrderSide = "buy"
+	SideSell OrderSide = "sell"
+)
+
+// 现货订单类型
+type OrderType string
+
+const (
+	OrderTypeBuyMarket        OrderType = "buy-market"          // 市价买, amount为买入金额
+	OrderTypeSellMarket       OrderType = "sell-market"         // 市价卖, amount为卖出数量
+	OrderTypeBuyLimit         OrderType = "buy-limit"           // 限价买
+	OrderTypeSellLimit        OrderType = "sell-limit"          // 限价卖
+	OrderTypeBuyIOC           OrderType = "buy-ioc"             // IOC买, 未立即成交的部分撤销
+	OrderTypeSellIOC          OrderType = "sell-ioc"            // IOC卖
+	OrderTypeBuyLimitMaker    OrderType = "buy-limit-maker"     // 只做maker买, 会立即成交时被拒绝
+	OrderTypeSellLimitMaker   OrderType = "sell-limit-maker"    // 只做maker卖
+	OrderTypeBuyStopLimit     OrderType = "buy-stop-limit"      // 止盈止损买
+	OrderTypeSellStopLimit    OrderType = "sell-stop-limit"     // 止盈止损卖
+	OrderTypeBuyLimitFOK      OrderType = "buy-limit-fok"       // FOK买, 不能全部成交时撤销
+	OrderTypeSellLimitFOK     OrderType = "sell-limit-fok"      // FOK卖
+	OrderTypeBuyStopLimitFOK  OrderType = "buy-stop-limit-fok"  // 止盈止损FOK买
+	OrderTypeSellStopLimitFOK OrderType = "sell-stop-limit-fok" // 止盈止损FOK卖
+)
+
+var orderTypes = map[OrderType]bool{
+	OrderTypeBuyMarket: true, OrderTypeSellMarket: true,
+	OrderTypeBuyLimit: true, OrderTypeSellLimit: true,
+	OrderTypeBuyIOC: true, OrderTypeSellIOC: true,
+	OrderTypeBuyLimitMaker: true, OrderTypeSellLimitMaker: true,
+	OrderTypeBuyStopLimit: true, OrderTypeSellStopLimit: true,
+	OrderTypeBuyLimitFOK: true, OrderTypeSellLimitFOK: true,
+	OrderTypeBuyStopLimitFOK: true, OrderTypeSellStopLimitFOK: true,
+}
+
+// 由方向和类型后缀组成订单类型, 如 NewOrderType(SideBuy, "limit-maker")
+func NewOrderType(side OrderSide, kind string) OrderType {
+	return OrderType(string(side) + "-" + kind)
+}
+
+// 是否为火币支持的订单类型
+func (t OrderType) IsValid() bool {
+	return orderTypes[t]
+}
+
+func (t OrderType) Side() OrderSide {
+	if strings.HasPrefix(string(t), "buy-") {
+		return SideBuy
+	}
+	return SideSell
+}
+
+func (t OrderType) IsBuy() bool {
+	return t.Side() == SideBuy
+}
+
+// 市价单不需要价格, 市价买单的amount为金额
+func (t OrderType) IsMarket() bool {
+	return strings.HasSuffix(string(t), "-market")
+}
+
+// 止盈止损单需要stop-price和operator
+func (t OrderType) IsStop() bool {
+	return strings.Contains(string(t), "-stop-limit")
+}
+
+// 止盈止损单的触发条件
+type StopOperator string
+
+const (
+	OperatorGTE StopOperator = "gte" // 最新价大于等于stop-price时触发
+	OperatorLTE StopOperator = "lte" // 最新价小于等于stop-price时触发
+)
+
+// 下单参数与订单类型不符
+var ErrInvalidOrderParams = errors.New("huobi: invalid order params")
+
+// 检查下单参数与订单类型是否匹配, 不检查交易对规则(见ValidateOrder)
+func (place *PlaceRequestParams) Validate() error {
+	if !place.Type.IsValid() {
+		return fmt.Errorf("%w: unknown type %s", ErrInvalidOrderParams, place.Type)
+	}
+	if place.Symbol == "" {
+		return fmt.Errorf("%w: symbol required", ErrInvalidOrderParams)
+	}
+	if !place.Amount.IsPositive() {
+		return fmt.Errorf("%w: amount must be positive", ErrInvalidOrderParams)
+	}
+	if place.Type.IsMarket() {
+		if !place.Price.IsZero() {
+			return fmt.Errorf("%w: price not allowed for %s", ErrInvalidOrderParams, place.Type)
+		}
+	} else if !place.Price.IsPositive() {
+		return fmt.Errorf("%w: price must be positive for %s", ErrInvalidOrderParams, place.Type)
+	}
+	if place.Type.IsStop() {
+		if !place.StopPrice.IsPositive() {
+			return fmt.Errorf("%w: stop-price required for %s", ErrInvalidOrderParams, place.Type)
+		}
+		if place.Operator != OperatorGTE && place.Operator != OperatorLTE {
+			return fmt.Errorf("%w: operator must be gte or lte, got %s", ErrInvalidOrderParams, place.Operator)
+		}
+	} else if !place.StopPrice.IsZero() || place.Operator != "" {
+		return fmt.Errorf("%w: stop-price and operator only allowed for stop-limit orders", ErrInvalidOrderParams)
+	}
+	return nil
+}
+
+// 构造下单参数
+// 例: huobi.NewOrderBuilder("btcusdt", huobi.SideBuy).LimitMaker(amount, price).ClientOrderID(id).Build()
+type OrderBuilder struct {
+	side   OrderSide
+	params PlaceRequestParams
+}
+
+// 未设置AccountID时, Exchange.PlaceBuilt使用Exchange的现货账户
+func NewOrderBuilder(symbol string, side OrderSide) *OrderBuilder {
+	return &OrderBuilder{side: side, params: PlaceRequestParams{Symbol: symbol, Source: "api"}}
+}
+
+func (b *OrderBuilder) kind(kind string, amount, price decimal.Decimal) *OrderBuilder {
+	b.params.Type = NewOrderType(b.side, kind)
+	b.params.Amount = amount
+	b.params.Price = price
+	return b
+}
+
+// 市价单, 买单amount为金额, 卖单amount为数量
+func (b *OrderBuilder) Market(amount decimal.Decimal) *OrderBuilder {
+	return b.kind("market", amount, decimal.Zero)
+}
+
+func (b *OrderBuilder) Limit(amount, price decimal.Decimal) *OrderBuilder {
+	return b.kind("limit", amount, price)
+}
+
+func (b *OrderBuilder) IOC(amount, price decimal.Decimal) *OrderBuilder {
+	return b.kind("ioc", amount, price)
+}
+
+func (b *OrderBuilder) FOK(amount, price decimal.Decimal) *OrderBuilder {
+	return b.kind("limit-fok", amount, price)
+}
+
+// 只做maker(post only)
+func (b *OrderBuilder) LimitMaker(amount, price decimal.Decimal) *OrderBuilder {
+	return b.kind("limit-maker", amount, price)
+}
+
+// 止盈止损单, 最新价满足operator和stopPrice的条件时以price挂单
+func (b *OrderBuilder) StopLimit(amount, price, stopPrice decimal.Decimal, operator StopOperator) *OrderBuilder {
+	b.kind("stop-limit", amount, price)
+	b.params.StopPrice = stopPrice
+	b.params.Operator = operator
+	return b
+}
+
+// 止盈止损FOK单
+func (b *OrderBuilder) StopLimitFOK(amount, price, stopPrice decimal.Decimal, operator StopOperator) *OrderBuilder {
+	b.kind("stop-limit-fok", amount, price)
+	b.params.StopPrice = stopPrice
+	b.params.Operator = operator
+	return b
+}
+
+func (b *OrderBuilder) AccountID(accountID string) *OrderBuilder {
+	b.params.AccountID = accountID
+	return b
+}
+
+// 订单来源, 如 spot-api, margin-api, super-margin-api
+func (b *OrderBuilder) Source(source string) *OrderBuilder {
+	b.params.Source = source
+	return b
+}
+
+func (b *OrderBuilder) ClientOrderID(clientOrderID string) *OrderBuilder {
+	b.params.ClientOrderID = clientOrderID
+	return b
+}
+
+// 返回检查过的下单参数副本
+func (b *OrderBuilder) Build() (*PlaceRequestParams, error) {
+	params := b.params
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	return &params, nil
+}
+
+// 用builder构造的参数下单, 返回订单ID
+func (ex *Exchange) PlaceBuilt(builder *OrderBuilder) (string, error) {
+	params, err := builder.Build()
+	if err != nil {
+		return "", err
+	}
+	if params.AccountID == "" {
+		params.AccountID = ex.accountId
+	}
+	placeReturn, err := ex.Place(params)
+	if err != nil {
+		return "", err
+	}
+	return placeReturn.Data, nil
+}
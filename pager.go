@@ -0,0 +1,73 @@
+package huobi
+
+import "strconv"
+
+// 逐项遍历分页结果的通用状态, OrderIterator和MatchResultIterator共用
+// fetch请求下一页, 返回该页的项数和是否还有下一页, 具体的项由调用方保存
+type pager struct {
+	fetch func() (int, bool, error)
+	count int
+	index int
+	more  bool
+	err   error
+}
+
+func newPager(fetch func() (int, bool, error)) pager {
+	return pager{fetch: fetch, index: -1, more: true}
+}
+
+// 移动到下一项, 当前页取完时请求下一页, 没有更多项或出错时返回false
+func (p *pager) next() bool {
+	p.index++
+	for p.index >= p.count {
+		if !p.more || p.err != nil {
+			return false
+		}
+		p.count, p.more, p.err = p.fetch()
+		p.index = 0
+	}
+	return true
+}
+
+// 当前项在页中的下标, 没有当前项时返回-1
+func (p *pager) current() int {
+	if p.index < 0 || p.index >= p.count {
+		return -1
+	}
+	return p.index
+}
+
+// 按from和size翻页的游标, /v1/order/orders和/v1/order/matchresults共用
+// 服务端是否在结果中包含from对应的项没有明确说明, 包含时去掉, 避免重复
+type fromCursor struct {
+	size int
+	from int64
+}
+
+// 请求前设置from参数, 第一页不设置
+func (c *fromCursor) apply(params map[string]string) {
+	if c.from != 0 {
+		params["from"] = strconv.FormatInt(c.from, 10)
+	} else {
+		delete(params, "from")
+	}
+}
+
+// 收到一页后调用, ids为该页各项的ID
+// 返回应跳过的开头项数, 以及该页是否取满(可能还有下一页)
+func (c *fromCursor) advance(ids []int64) (int, bool) {
+	full := len(ids) == c.size
+	skip := 0
+	if c.from != 0 && len(ids) > 0 && ids[0] == c.from {
+		skip = 1
+	}
+	if len(ids) > skip {
+		c.from = ids[len(ids)-1]
+	}
+	return skip, full && len(ids) > skip
+}
+
+// 从第一页重新开始, 如进入下一个时间窗口
+func (c *fromCursor) reset() {
+	c.from = 0
+}
@@ -0,0 +1,89 @@
+package huobi
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestFromCursor(t *testing.T) {
+	cursor := &fromCursor{size: 3}
+	params := make(map[string]string)
+
+	cursor.apply(params)
+	if _, ok := params["from"]; ok {
+		t.Fatalf("first page sets from = %s", params["from"])
+	}
+	if skip, full := cursor.advance([]int64{9, 8, 7}); skip != 0 || !full {
+		t.Errorf("first page: skip, full = %d, %v, want 0, true", skip, full)
+	}
+
+	cursor.apply(params)
+	if params["from"] != "7" {
+		t.Fatalf("from = %s, want 7", params["from"])
+	}
+	// 服务端在结果中包含from对应的项
+	if skip, full := cursor.advance([]int64{7, 6, 5}); skip != 1 || !full {
+		t.Errorf("inclusive page: skip, full = %d, %v, want 1, true", skip, full)
+	}
+	cursor.apply(params)
+	if params["from"] != "5" {
+		t.Fatalf("from = %s, want 5", params["from"])
+	}
+	// 只返回from对应的项时没有新的项, 不再翻页
+	if skip, full := cursor.advance([]int64{5}); skip != 1 || full {
+		t.Errorf("last page: skip, full = %d, %v, want 1, false", skip, full)
+	}
+
+	cursor.reset()
+	cursor.apply(params)
+	if _, ok := params["from"]; ok {
+		t.Errorf("reset cursor sets from = %s", params["from"])
+	}
+}
+
+func TestTimeWindows(t *testing.T) {
+	end := time.Unix(1600000000, 0)
+	millis := func(t time.Time) string {
+		return strconv.FormatInt(unixMillis(t), 10)
+	}
+	ms := time.Millisecond
+
+	windows := &timeWindows{start: end.Add(-100 * time.Hour), end: end, size: 48 * time.Hour}
+	want := [][2]time.Time{
+		{end.Add(-48*time.Hour + ms), end},
+		{end.Add(-96*time.Hour + ms), end.Add(-48 * time.Hour)},
+		{end.Add(-100 * time.Hour), end.Add(-96 * time.Hour)},
+	}
+	params := make(map[string]string)
+	for i, window := range want {
+		if !windows.next(params) {
+			t.Fatalf("window %d missing", i)
+		}
+		if params["start-time"] != millis(window[0]) || params["end-time"] != millis(window[1]) {
+			t.Errorf("window %d = [%s, %s], want [%s, %s]", i, params["start-time"], params["end-time"], millis(window[0]), millis(window[1]))
+		}
+	}
+	if windows.next(params) {
+		t.Errorf("extra window [%s, %s]", params["start-time"], params["end-time"])
+	}
+
+	// 未指定开始时间时只有一个窗口, 由服务端决定开始时间
+	windows = &timeWindows{end: end, size: 48 * time.Hour}
+	params = map[string]string{"start-time": "1"}
+	if !windows.next(params) || params["end-time"] != millis(end) {
+		t.Fatalf("open window end-time = %s, want %s", params["end-time"], millis(end))
+	}
+	if _, ok := params["start-time"]; ok {
+		t.Errorf("open window sets start-time = %s", params["start-time"])
+	}
+	if windows.next(params) {
+		t.Error("open range has more than one window")
+	}
+
+	// 结束时间早于开始时间时没有窗口
+	windows = &timeWindows{start: end, end: end.Add(-time.Hour), size: 48 * time.Hour}
+	if windows.next(params) {
+		t.Error("empty range has a window")
+	}
+}
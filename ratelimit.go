@@ -0,0 +1,193 @@
+package huobi
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// 限频分组, 火币按UID和接口类别分别计算请求额度
+const (
+	RateLimitMarket   = "market"   // 行情及公共接口
+	RateLimitOrder    = "order"    // 现货下单、撤单、查单
+	RateLimitAccount  = "account"  // 现货账户等其他私有接口
+	RateLimitContract = "contract" // 交割、永续、期权合约接口
+)
+
+// 限频器以快速失败模式拒绝请求时返回的错误
+var ErrRateLimited = errors.New("huobi: rate limit exceeded")
+
+// 限频器, Exchange在每次请求前调用Wait, 收到响应后调用Update
+// accessKey为签名请求的AccessKeyId, 公共接口为空; 火币按API Key分别计算私有接口的额度
+type RateLimiter interface {
+	// 等待accessKey在group中有可用额度, 无法等待时返回错误
+	Wait(ctx context.Context, group, accessKey string) error
+	// 根据响应头同步服务端的剩余额度, remain为剩余次数, expire为额度重置时间
+	Update(group, accessKey string, remain int, expire time.Time)
+}
+
+// 一个分组的额度: 每秒补充Rate次, 最多累积Burst次
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// 火币文档中各分组的默认额度
+func DefaultRateLimits() map[string]RateLimit {
+	return map[string]RateLimit{
+		RateLimitMarket:   {Rate: 80, Burst: 80},  // 每IP 800次/10秒
+		RateLimitOrder:    {Rate: 50, Burst: 100}, // 每UID 100次/2秒
+		RateLimitAccount:  {Rate: 10, Burst: 100}, // 每UID 100次/10秒
+		RateLimitContract: {Rate: 24, Burst: 72},  // 每UID 72次/3秒
+	}
+}
+
+type tokenBucket struct {
+	limit  RateLimit
+	tokens float64
+	last   time.Time
+	// 服务端告知额度用尽时, 在此之前不放行
+	blockedUntil time.Time
+}
+
+// 按分组和API Key计数的令牌桶限频器, 同一分组的每个Key各有一份limits中的额度
+type TokenBucketLimiter struct {
+	mu       sync.Mutex
+	limits   map[string]RateLimit
+	buckets  map[string]*tokenBucket
+	failFast bool
+}
+
+// 创建令牌桶限频器, limits中没有的分组不限频
+// failFast为true时额度不足立即返回ErrRateLimited, 否则阻塞等待
+func NewTokenBucketLimiter(limits map[string]RateLimit, failFast bool) *TokenBucketLimiter {
+	return &TokenBucketLimiter{
+		limits:   limits,
+		buckets:  make(map[string]*tokenBucket),
+		failFast: failFast,
+	}
+}
+
+func (l *TokenBucketLimiter) Wait(ctx context.Context, group, accessKey string) error {
+	for {
+		wait, ok := l.reserve(group, accessKey, time.Now())
+		if ok {
+			return nil
+		}
+		if l.failFast {
+			return ErrRateLimited
+		}
+		if err := sleepContext(ctx, wait); err != nil {
+			return err
+		}
+	}
+}
+
+func (l *TokenBucketLimiter) Update(group, accessKey string, remain int, expire time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket := l.bucket(group, accessKey, time.Now())
+	if bucket == nil {
+		return
+	}
+	if float64(remain) < bucket.tokens {
+		bucket.tokens = float64(remain)
+	}
+	if remain <= 0 && expire.After(bucket.blockedUntil) {
+		bucket.blockedUntil = expire
+	}
+}
+
+// 尝试取出一个令牌, 失败时返回需要等待的时间
+func (l *TokenBucketLimiter) reserve(group, accessKey string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket := l.bucket(group, accessKey, now)
+	if bucket == nil {
+		return 0, true
+	}
+	if now.Before(bucket.blockedUntil) {
+		return bucket.blockedUntil.Sub(now), false
+	}
+	if bucket.tokens >= 1 {
+		bucket.tokens--
+		return 0, true
+	}
+	if bucket.limit.Rate <= 0 {
+		return time.Second, false
+	}
+	return time.Duration((1 - bucket.tokens) / bucket.limit.Rate * float64(time.Second)), false
+}
+
+// 取出分组中accessKey的令牌桶并按流逝的时间补充令牌, 分组不限频时返回nil
+func (l *TokenBucketLimiter) bucket(group, accessKey string, now time.Time) *tokenBucket {
+	name := group + " " + accessKey
+	bucket, ok := l.buckets[name]
+	if !ok {
+		limit, ok := l.limits[group]
+		if !ok {
+			return nil
+		}
+		bucket = &tokenBucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		l.buckets[name] = bucket
+	}
+
+	elapsed := now.Sub(bucket.last).Seconds()
+	if elapsed > 0 {
+		bucket.tokens += elapsed * bucket.limit.Rate
+		if bucket.tokens > float64(bucket.limit.Burst) {
+			bucket.tokens = float64(bucket.limit.Burst)
+		}
+		bucket.last = now
+	}
+	return bucket
+}
+
+// 使用限频器, 默认不限频
+func WithRateLimiter(limiter RateLimiter) Option {
+	return func(ex *Exchange) {
+		ex.rateLimiter = limiter
+	}
+}
+
+// 合约接口的路径前缀, 现货和合约使用同一地址时也按路径区分
+var contractPathPrefixes = []string{"/api/", "/swap-api/", "/linear-swap-api/", "/option-api/"}
+
+// 根据请求路径判断所属的限频分组
+func rateLimitGroup(path string) string {
+	for _, prefix := range contractPathPrefixes {
+		if strings.HasPrefix(path, prefix) {
+			return RateLimitContract
+		}
+	}
+	switch {
+	case strings.HasPrefix(path, "/market"), strings.HasPrefix(path, "/v1/common"), strings.HasPrefix(path, "/v2/settings"), strings.HasPrefix(path, "/v2/market-status"):
+		return RateLimitMarket
+	case strings.HasPrefix(path, "/v1/order"), strings.HasPrefix(path, "/v2/algo-orders"):
+		return RateLimitOrder
+	}
+	return RateLimitAccount
+}
+
+// 读取X-HB-RateLimit-Requests-Remain/Expire响应头, 没有时忽略
+func updateRateLimit(limiter RateLimiter, group, accessKey string, header http.Header) {
+	remain := header.Get("X-HB-RateLimit-Requests-Remain")
+	if remain == "" {
+		return
+	}
+	nRemain, err := strconv.Atoi(remain)
+	if err != nil {
+		return
+	}
+	var expire time.Time
+	if ms, err := strconv.ParseInt(header.Get("X-HB-RateLimit-Requests-Expire"), 10, 64); err == nil {
+		expire = time.Unix(0, ms*int64(time.Millisecond))
+	}
+	limiter.Update(group, accessKey, nRemain, expire)
+}
@@ -0,0 +1,63 @@
+package huobi_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/monkeybang/huobi"
+	"github.com/monkeybang/huobi/huobitest"
+)
+
+func TestTokenBucketLimiterPerKey(t *testing.T) {
+	limiter := huobi.NewTokenBucketLimiter(huobi.DefaultRateLimits(), true)
+	ctx := context.Background()
+
+	limiter.Update(huobi.RateLimitOrder, "key1", 0, time.Now().Add(time.Minute))
+	if err := limiter.Wait(ctx, huobi.RateLimitOrder, "key1"); !errors.Is(err, huobi.ErrRateLimited) {
+		t.Errorf("key1 wait = %v, want ErrRateLimited", err)
+	}
+	if err := limiter.Wait(ctx, huobi.RateLimitOrder, "key2"); err != nil {
+		t.Errorf("key2 wait = %v, want nil", err)
+	}
+	if err := limiter.Wait(ctx, huobi.RateLimitAccount, "key1"); err != nil {
+		t.Errorf("key1 account wait = %v, want nil", err)
+	}
+}
+
+// 记录每次Wait的分组和AccessKey
+type recordLimiter struct {
+	mu    sync.Mutex
+	waits []string
+}
+
+func (l *recordLimiter) Wait(ctx context.Context, group, accessKey string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waits = append(l.waits, group+" "+accessKey)
+	return nil
+}
+
+func (l *recordLimiter) Update(group, accessKey string, remain int, expire time.Time) {}
+
+func TestRateLimiterReceivesAccessKey(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	limiter := &recordLimiter{}
+	ex := openExchange(t, server, huobi.WithRateLimiter(limiter))
+	defer ex.Close()
+
+	limiter.waits = nil
+	if _, err := ex.GetTimestamp(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ex.OpenOrders("btcusdt"); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{huobi.RateLimitMarket + " ", huobi.RateLimitOrder + " ak"}
+	if len(limiter.waits) != len(want) || limiter.waits[0] != want[0] || limiter.waits[1] != want[1] {
+		t.Errorf("waits = %q, want %q", limiter.waits, want)
+	}
+}
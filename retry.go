@@ -0,0 +1,130 @@
+package huobi
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"syscall"
+	"time"
+)
+
+// 重试策略, 按指数退避加随机抖动重试可重试的错误
+type RetryPolicy struct {
+	MaxAttempts    int              // 最多尝试次数(含第一次), 小于等于1时不重试
+	InitialBackoff time.Duration    // 第一次重试前的等待时间
+	MaxBackoff     time.Duration    // 等待时间上限, 0为不限
+	Multiplier     float64          // 每次重试等待时间的倍数, 小于1时按1处理
+	Jitter         float64          // 随机抖动比例, 0.2表示在等待时间上下浮动20%
+	Retryable      func(error) bool // 判断错误能否重试, 为nil时使用IsRetryable
+}
+
+// 默认重试策略: 最多3次, 从200ms开始翻倍退避
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: 200 * time.Millisecond,
+		MaxBackoff:     2 * time.Second,
+		Multiplier:     2,
+		Jitter:         0.2,
+	}
+}
+
+// 执行fn, 返回可重试的错误时按策略等待后重试, ctx结束时停止
+func (p *RetryPolicy) Do(ctx context.Context, fn func() error) error {
+	var err error
+	for attempt := 1; ; attempt++ {
+		err = fn()
+		if err == nil || attempt >= p.MaxAttempts || !p.retryable(err) {
+			return err
+		}
+		if sleepErr := sleepContext(ctx, p.Backoff(attempt)); sleepErr != nil {
+			return err
+		}
+	}
+}
+
+// 第attempt次失败后应等待的时间
+func (p *RetryPolicy) Backoff(attempt int) time.Duration {
+	multiplier := p.Multiplier
+	if multiplier < 1 {
+		multiplier = 1
+	}
+	backoff := float64(p.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if p.MaxBackoff > 0 && backoff > float64(p.MaxBackoff) {
+		backoff = float64(p.MaxBackoff)
+	}
+	if p.Jitter > 0 {
+		backoff += backoff * p.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(backoff)
+}
+
+func (p *RetryPolicy) retryable(err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	return IsRetryable(err)
+}
+
+// 可重试的火币错误码
+var retryableErrCodes = map[string]bool{
+	ErrCodeInternal:        true,
+	ErrCodeTooManyRequests: true,
+	"base-system-error":    true,
+	"order-update-error":   true,
+	"1032":                 true, // 合约接口访问次数超出限制
+}
+
+// 判断错误能否重试: 请求超时、连接被重置或拒绝、连接意外关闭、限频、服务端5xx及内部错误可以重试,
+// 余额不足、参数错误、签名错误等业务错误, 以及证书错误、域名解析失败等网络错误重试也不会成功
+// 调用方的ctx被取消或超时时不重试
+func IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, context.Canceled) || callerDeadline(err) {
+		return false
+	}
+	if errors.Is(err, ErrRateLimited) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, io.EOF) {
+		return true
+	}
+	if errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	if apiErr := AsAPIError(err); apiErr != nil {
+		return apiErr.HTTPStatus >= http.StatusInternalServerError || apiErr.IsRateLimited() || retryableErrCodes[apiErr.Code]
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// 错误链中是否有context.DeadlineExceeded本身, 即调用方的ctx超时
+// http.Client.Timeout超时的错误只通过Is方法匹配context.DeadlineExceeded, 仍可重试
+func callerDeadline(err error) bool {
+	for ; err != nil; err = errors.Unwrap(err) {
+		if err == context.DeadlineExceeded {
+			return true
+		}
+	}
+	return false
+}
+
+// GET等幂等请求按policy重试, 默认不重试
+// POST请求不重试, 避免重复下单或撤单
+func WithRetryPolicy(policy *RetryPolicy) Option {
+	return func(ex *Exchange) {
+		ex.retryPolicy = policy
+	}
+}
+
+// 返回按policy重试幂等请求的Exchange副本
+// 例: ex.WithRetry(huobi.DefaultRetryPolicy()).GetOrder(orderId)
+func (ex *Exchange) WithRetry(policy *RetryPolicy) *Exchange {
+	ex2 := *ex
+	ex2.retryPolicy = policy
+	return &ex2
+}
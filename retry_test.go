@@ -0,0 +1,117 @@
+package huobi_test
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/monkeybang/huobi"
+	"github.com/monkeybang/huobi/huobitest"
+	"github.com/shopspring/decimal"
+)
+
+func countRequests(server *huobitest.Server, method, path string) int {
+	n := 0
+	for _, request := range server.Requests() {
+		if request.Method == method && request.Path == path {
+			n++
+		}
+	}
+	return n
+}
+
+func TestRetryPolicySkipsPost(t *testing.T) {
+	server := huobitest.NewServer("ak", "sk")
+	defer server.Close()
+	policy := &huobi.RetryPolicy{MaxAttempts: 3, InitialBackoff: time.Millisecond}
+	ex := openExchange(t, server, huobi.WithRetryPolicy(policy))
+	defer ex.Close()
+
+	server.FailNext(http.MethodPost, "/v1/order/orders/place", 1, http.StatusBadGateway, huobi.ErrCodeInternal, "bad gateway")
+	_, err := ex.PlaceOrder("btcusdt", huobi.OrderTypeBuyLimit, decimal.New(1, 0), decimal.New(100, 0))
+	if !huobi.IsRetryable(err) {
+		t.Fatalf("place error = %v, want retryable error", err)
+	}
+	if n := countRequests(server, http.MethodPost, "/v1/order/orders/place"); n != 1 {
+		t.Errorf("place sent %d times, want 1", n)
+	}
+
+	server.FailNext(http.MethodGet, "/v1/order/openOrders", 2, http.StatusBadGateway, huobi.ErrCodeInternal, "bad gateway")
+	if _, err := ex.OpenOrders("btcusdt"); err != nil {
+		t.Fatalf("open orders: %v", err)
+	}
+	if n := countRequests(server, http.MethodGet, "/v1/order/openOrders"); n != 3 {
+		t.Errorf("open orders sent %d times, want 3", n)
+	}
+}
+
+// 实现net.Error的超时错误
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func urlError(err error) error {
+	return &url.Error{Op: "Get", URL: "https://api.huobi.pro/v1/order/openOrders", Err: err}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"timeout", urlError(&net.OpError{Op: "read", Net: "tcp", Err: timeoutError{}}), true},
+		{"connection reset", urlError(&net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}), true},
+		{"connection refused", urlError(&net.OpError{Op: "dial", Net: "tcp", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}), true},
+		{"eof", urlError(io.EOF), true},
+		{"unexpected eof", urlError(io.ErrUnexpectedEOF), true},
+		{"rate limited", fmt.Errorf("wait: %w", huobi.ErrRateLimited), true},
+		{"server error", &huobi.APIError{HTTPStatus: http.StatusBadGateway}, true},
+		{"internal error code", &huobi.APIError{HTTPStatus: http.StatusOK, Code: huobi.ErrCodeInternal}, true},
+		{"balance insufficient", &huobi.APIError{HTTPStatus: http.StatusOK, Code: huobi.ErrCodeBalanceInsufficient}, false},
+		{"canceled", urlError(context.Canceled), false},
+		{"caller deadline", urlError(context.DeadlineExceeded), false},
+		{"deadline", context.DeadlineExceeded, false},
+		{"certificate", urlError(x509.UnknownAuthorityError{}), false},
+		{"unknown host", urlError(&net.OpError{Op: "dial", Net: "tcp", Err: &net.DNSError{Err: "no such host", Name: "api.huobi.pro"}}), false},
+		{"unsupported scheme", urlError(errors.New(`unsupported protocol scheme "ftp"`)), false},
+	}
+	for _, test := range tests {
+		if got := huobi.IsRetryable(test.err); got != test.want {
+			t.Errorf("%s: IsRetryable(%v) = %v, want %v", test.name, test.err, got, test.want)
+		}
+	}
+}
+
+// http.Client.Timeout超时可以重试, 调用方ctx超时不重试
+func TestIsRetryableTimeouts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Timeout: 10 * time.Millisecond}
+	if _, err := client.Get(server.URL); !huobi.IsRetryable(err) {
+		t.Errorf("client timeout %v: not retryable", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	request, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	if _, err := http.DefaultClient.Do(request.WithContext(ctx)); huobi.IsRetryable(err) {
+		t.Errorf("caller deadline %v: retryable", err)
+	}
+}
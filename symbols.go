@@ -0,0 +1,202 @@
+package huobi
+
+import (
+	"sync"
+	"time"
+)
+
+// 交易对变化的类型
+type SymbolEventType int
+
+const (
+	SymbolListed           SymbolEventType = iota // 新上线的交易对
+	SymbolDelisted                                // 交易对已下线或不再出现在列表中
+	SymbolStateChanged                            // 状态变化, 如暂停交易、禁止API交易
+	SymbolPrecisionChanged                        // 价格、数量或金额精度变化
+)
+
+func (t SymbolEventType) String() string {
+	switch t {
+	case SymbolListed:
+		return "listed"
+	case SymbolDelisted:
+		return "delisted"
+	case SymbolStateChanged:
+		return "state-changed"
+	case SymbolPrecisionChanged:
+		return "precision-changed"
+	}
+	return "unknown"
+}
+
+// 交易对变化, Old或New在上线和下线时为nil
+type SymbolEvent struct {
+	Type   SymbolEventType
+	Symbol string
+	Old    *SymbolsData
+	New    *SymbolsData
+}
+
+// 交易对变化的通知函数, 在刷新交易对的goroutine中调用, 不应长时间阻塞
+type SymbolListener func(event SymbolEvent)
+
+// 交易对信息缓存, 构造Exchange时加载, 设置WithSymbolRefresh后定时刷新
+// 同一Exchange的副本(WithContext等)共享同一个缓存
+type SymbolRegistry struct {
+	mu        sync.RWMutex
+	symbols   map[string]*SymbolsData
+	listeners []SymbolListener
+	updatedAt time.Time
+
+	interval time.Duration
+	stop     chan struct{}
+}
+
+func newSymbolRegistry() *SymbolRegistry {
+	return &SymbolRegistry{symbols: make(map[string]*SymbolsData)}
+}
+
+// 按名称查找交易对, 如 btcusdt
+func (r *SymbolRegistry) Get(symbol string) (*SymbolsData, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	data, ok := r.symbols[symbol]
+	return data, ok
+}
+
+// 所有交易对的副本, key为交易对名称
+func (r *SymbolRegistry) All() map[string]*SymbolsData {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	symbols := make(map[string]*SymbolsData, len(r.symbols))
+	for name, data := range r.symbols {
+		symbols[name] = data
+	}
+	return symbols
+}
+
+// 最近一次刷新的时间
+func (r *SymbolRegistry) UpdatedAt() time.Time {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.updatedAt
+}
+
+// 注册交易对变化的通知函数
+func (r *SymbolRegistry) Subscribe(listener SymbolListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, listener)
+}
+
+// 用新的交易对列表替换缓存, 并通知变化
+// 首次加载时不通知上线事件
+func (r *SymbolRegistry) update(list []*SymbolsData) {
+	symbols := make(map[string]*SymbolsData, len(list))
+	for _, data := range list {
+		symbols[data.Name()] = data
+	}
+
+	r.mu.Lock()
+	var events []SymbolEvent
+	if !r.updatedAt.IsZero() {
+		events = diffSymbols(r.symbols, symbols)
+	}
+	r.symbols = symbols
+	r.updatedAt = time.Now()
+	listeners := r.listeners
+	r.mu.Unlock()
+
+	for _, event := range events {
+		for _, listener := range listeners {
+			listener(event)
+		}
+	}
+}
+
+// 比较两次加载的交易对, 生成变化事件
+func diffSymbols(old, new map[string]*SymbolsData) []SymbolEvent {
+	var events []SymbolEvent
+	for name, oldData := range old {
+		newData, ok := new[name]
+		switch {
+		case !ok:
+			events = append(events, SymbolEvent{Type: SymbolDelisted, Symbol: name, Old: oldData})
+		case newData.State == "offline" && oldData.State != "offline":
+			events = append(events, SymbolEvent{Type: SymbolDelisted, Symbol: name, Old: oldData, New: newData})
+		case newData.State != oldData.State || newData.ApiTrading != oldData.ApiTrading:
+			events = append(events, SymbolEvent{Type: SymbolStateChanged, Symbol: name, Old: oldData, New: newData})
+		}
+		if ok && (newData.PricePrecision != oldData.PricePrecision ||
+			newData.AmountPrecision != oldData.AmountPrecision ||
+			newData.ValuePrecision != oldData.ValuePrecision) {
+			events = append(events, SymbolEvent{Type: SymbolPrecisionChanged, Symbol: name, Old: oldData, New: newData})
+		}
+	}
+	for name, newData := range new {
+		if _, ok := old[name]; !ok {
+			events = append(events, SymbolEvent{Type: SymbolListed, Symbol: name, New: newData})
+		}
+	}
+	return events
+}
+
+// 构造后每隔interval刷新交易对, 需调用Close停止
+func WithSymbolRefresh(interval time.Duration) Option {
+	return func(ex *Exchange) {
+		ex.symbols.interval = interval
+	}
+}
+
+// 交易对缓存
+func (ex *Exchange) Symbols() *SymbolRegistry {
+	return ex.symbols
+}
+
+// 立即从/v1/common/symbols刷新交易对缓存
+func (ex *Exchange) RefreshSymbols() error {
+	symbolsReturn, err := ex.QuerySymbols()
+	if err != nil {
+		return err
+	}
+	ex.symbols.update(symbolsReturn.Data)
+	return nil
+}
+
+// 启动后台定时刷新, 已启动或未设置间隔时不做任何事
+func (ex *Exchange) startSymbolRefresh() {
+	r := ex.symbols
+	if r.interval <= 0 {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stop != nil {
+		return
+	}
+	r.stop = make(chan struct{})
+
+	go func(stop chan struct{}) {
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if err := ex.RefreshSymbols(); err != nil {
+					ex.log().Warn("refresh symbols error", "error", err)
+				}
+			}
+		}
+	}(r.stop)
+}
+
+func (r *SymbolRegistry) close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.stop != nil {
+		close(r.stop)
+		r.stop = nil
+	}
+}
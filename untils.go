@@ -1,296 +1,421 @@
 package huobi
 
 import (
+	"context"
 	"crypto/hmac"
 	"crypto/sha256"
 	"encoding/base64"
 	"encoding/json"
 	"io/ioutil"
+	"net"
 	"net/http"
-	"net/url"
 	"sort"
 	"strings"
 	"time"
 )
 
+// 未指定超时时的默认请求超时
+var DefaultTimeout = 10 * time.Second
+
+// 默认HTTP客户端, 包级请求函数以及未指定客户端的Exchange共用, 复用连接池
+var DefaultHttpClient = NewHttpClient(DefaultTimeout)
+
+// 创建带超时和连接池的HTTP客户端
+func NewHttpClient(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: NewTransport(),
+	}
+}
+
+// 创建默认的Transport: 读取环境变量中的代理, 保持长连接, 并限制握手时间
+func NewTransport() *http.Transport {
+	return &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   5 * time.Second,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   20,
+		IdleConnTimeout:       90 * time.Second,
+		TLSHandshakeTimeout:   5 * time.Second,
+		ExpectContinueTimeout: 1 * time.Second,
+	}
+}
+
 // Http Get请求基础函数, 通过封装Go语言Http请求, 支持火币网REST API的HTTP Get请求
 // strUrl: 请求的URL
-// strParams: string类型的请求参数, user=lxz&pwd=lxz
+// mapParams: map类型的请求参数, 值无需预先编码, 按EncodeParams拼接
+// return: 请求结果
+func HttpGetRequest(strUrl string, mapParams map[string]string) (string, error) {
+	return HttpGetRequestWithContext(context.Background(), strUrl, mapParams)
+}
+
+// 同HttpGetRequest, 请求随ctx取消或超时
+func HttpGetRequestWithContext(ctx context.Context, strUrl string, mapParams map[string]string) (string, error) {
+	request, err := newGetRequest(ctx, strUrl, mapParams)
+	if nil != err {
+		return "", err
+	}
+	_, body, err := doRequest(DefaultHttpClient, request)
+	return body, err
+}
+
+func HttpPostRequestBatchorder(strUrl string, mapParams map[string]interface{}) (string, error) {
+	return HttpPostRequestBatchorderWithContext(context.Background(), strUrl, mapParams)
+}
+
+// 同HttpPostRequestBatchorder, 请求随ctx取消或超时
+func HttpPostRequestBatchorderWithContext(ctx context.Context, strUrl string, mapParams map[string]interface{}) (string, error) {
+	var params interface{}
+	if nil != mapParams {
+		params = mapParams
+	}
+	return httpPostRequest(ctx, strUrl, params)
+}
+
+// Http POST请求基础函数, 通过封装Go语言Http请求, 支持火币网REST API的HTTP POST请求
+// strUrl: 请求的URL
+// mapParams: map类型的请求参数
 // return: 请求结果
-func HttpGetRequest(strUrl string, mapParams map[string]string) string {
-	httpClient := &http.Client{}
+func HttpPostRequest(strUrl string, mapParams map[string]string) (string, error) {
+	return HttpPostRequestWithContext(context.Background(), strUrl, mapParams)
+}
+
+// 同HttpPostRequest, 请求随ctx取消或超时
+func HttpPostRequestWithContext(ctx context.Context, strUrl string, mapParams map[string]string) (string, error) {
+	var params interface{}
+	if nil != mapParams {
+		params = mapParams
+	}
+	return httpPostRequest(ctx, strUrl, params)
+}
 
+func httpPostRequest(ctx context.Context, strUrl string, params interface{}) (string, error) {
+	request, err := newPostRequest(ctx, strUrl, params)
+	if nil != err {
+		return "", err
+	}
+	_, body, err := doRequest(DefaultHttpClient, request)
+	return body, err
+}
+
+func newGetRequest(ctx context.Context, strUrl string, mapParams map[string]string) (*http.Request, error) {
 	var strRequestUrl string
 	if nil == mapParams {
 		strRequestUrl = strUrl
 	} else {
-		strParams := Map2UrlQuery(mapParams)
+		strParams := EncodeParams(mapParams)
 		strRequestUrl = strUrl + "?" + strParams
 	}
 
 	// 构建Request, 并且按官方要求添加Http Header
-	request, err := http.NewRequest("GET", strRequestUrl, nil)
+	request, err := http.NewRequestWithContext(ctx, "GET", strRequestUrl, nil)
 	if nil != err {
-		return err.Error()
+		return nil, err
 	}
 	request.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/39.0.2171.71 Safari/537.36")
-
-	// 发出请求
-	response, err := httpClient.Do(request)
-	if nil != err {
-		return err.Error()
-	}
-	defer response.Body.Close()
-	// 解析响应内容
-	body, err := ioutil.ReadAll(response.Body)
-	if nil != err {
-		return err.Error()
-	}
-
-	return string(body)
+	return request, nil
 }
-func HttpPostRequestBatchorder(strUrl string, mapParams map[string]interface{}) string {
-	httpClient := &http.Client{}
 
+func newPostRequest(ctx context.Context, strUrl string, params interface{}) (*http.Request, error) {
 	jsonParams := ""
-	if nil != mapParams {
-		bytesParams, _ := json.Marshal(mapParams)
+	if nil != params {
+		bytesParams, err := json.Marshal(params)
+		if nil != err {
+			return nil, err
+		}
 		jsonParams = string(bytesParams)
 	}
 
-	request, err := http.NewRequest("POST", strUrl, strings.NewReader(jsonParams))
+	request, err := http.NewRequestWithContext(ctx, "POST", strUrl, strings.NewReader(jsonParams))
 	if nil != err {
-		return err.Error()
+		return nil, err
 	}
-	//request.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/39.0.2171.71 Safari/537.36")
 	request.Header.Add("Content-Type", "application/json")
-	//request.Header.Add("Accept-Language", "zh-cn")
+	return request, nil
+}
 
+// 发出请求并读取响应内容, 网络错误原样返回, 接口错误返回*APIError
+// 出现接口错误时仍然返回响应内容, 方便调用方查看
+func doRequest(httpClient *http.Client, request *http.Request) (*http.Response, string, error) {
 	response, err := httpClient.Do(request)
-	defer response.Body.Close()
 	if nil != err {
-		return err.Error()
+		return nil, "", redactError(err)
 	}
+	defer response.Body.Close()
 
 	body, err := ioutil.ReadAll(response.Body)
 	if nil != err {
-		return err.Error()
+		return response, "", err
 	}
 
-	return string(body)
+	return response, string(body), checkResponse(response.StatusCode, string(body))
 }
 
-// Http POST请求基础函数, 通过封装Go语言Http请求, 支持火币网REST API的HTTP POST请求
-// strUrl: 请求的URL
-// mapParams: map类型的请求参数
-// return: 请求结果
-func HttpPostRequest(strUrl string, mapParams map[string]string) string {
-	httpClient := &http.Client{}
+// 等待d, ctx结束时提前返回ctx.Err()
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
 
-	jsonParams := ""
-	if nil != mapParams {
-		bytesParams, _ := json.Marshal(mapParams)
-		jsonParams = string(bytesParams)
+// Exchange使用的HTTP客户端, 未指定时使用DefaultHttpClient
+func (ex *Exchange) client() *http.Client {
+	if ex.httpClient != nil {
+		return ex.httpClient
 	}
+	return DefaultHttpClient
+}
 
-	request, err := http.NewRequest("POST", strUrl, strings.NewReader(jsonParams))
+func (ex *Exchange) httpGet(ctx context.Context, strUrl string, mapParams map[string]string) (string, error) {
+	request, err := newGetRequest(ctx, strUrl, mapParams)
 	if nil != err {
-		return err.Error()
+		return "", err
 	}
-	//request.Header.Add("User-Agent", "Mozilla/5.0 (Windows NT 6.1; WOW64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/39.0.2171.71 Safari/537.36")
-	request.Header.Add("Content-Type", "application/json")
-	//request.Header.Add("Accept-Language", "zh-cn")
+	return ex.do(request)
+}
 
-	response, err := httpClient.Do(request)
-	defer response.Body.Close()
+func (ex *Exchange) httpPost(ctx context.Context, strUrl string, params interface{}) (string, error) {
+	request, err := newPostRequest(ctx, strUrl, params)
 	if nil != err {
-		return err.Error()
+		return "", err
 	}
+	return ex.do(request)
+}
 
-	body, err := ioutil.ReadAll(response.Body)
-	if nil != err {
-		return err.Error()
+// Exchange发出请求的统一入口, 设置了重试策略时按策略重试幂等请求
+// 下单、撤单等POST请求失败时服务端可能已经处理, 不在此重试, 下单需重试时使用PlaceWithRetry
+func (ex *Exchange) do(request *http.Request) (string, error) {
+	if ex.retryPolicy == nil || !idempotent(request.Method) {
+		return ex.doOnce(request)
 	}
 
-	return string(body)
+	var body string
+	err := ex.retryPolicy.Do(request.Context(), func() error {
+		attempt := request.Clone(request.Context())
+		if request.GetBody != nil {
+			reqBody, err := request.GetBody()
+			if err != nil {
+				return err
+			}
+			attempt.Body = reqBody
+		}
+		var err error
+		body, err = ex.doOnce(attempt)
+		return err
+	})
+	return body, err
 }
 
-func (ex *Exchange) ContractKeyGet(mapParams map[string]string, strRequestPath string) string {
-	strMethod := "GET"
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05")
-
-	mapParams["AccessKeyId"] = ex.accessKey
-	mapParams["SignatureMethod"] = "HmacSHA256"
-	mapParams["SignatureVersion"] = "2"
-	mapParams["Timestamp"] = timestamp
+// 重复发出不会产生额外影响的请求方法
+func idempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
 
-	hostName := HOST_CONTRACT
-	mapParams["Signature"] = CreateSign(mapParams, strMethod, hostName, strRequestPath, ex.secretKey)
+// 发出一次请求, 请求前等待限频器放行, 经过中间件链发出后根据响应头更新剩余额度
+func (ex *Exchange) doOnce(request *http.Request) (string, error) {
+	group := rateLimitGroup(request.URL.Path)
+	accessKey := request.URL.Query().Get("AccessKeyId")
+	if ex.rateLimiter != nil {
+		if err := ex.rateLimiter.Wait(request.Context(), group, accessKey); err != nil {
+			return "", err
+		}
+	}
 
-	strUrl := CONTRACT_URL + strRequestPath
+	response, err := ex.handler()(newMiddlewareRequest(request, group))
+	if response == nil {
+		return "", err
+	}
+	if ex.rateLimiter != nil {
+		updateRateLimit(ex.rateLimiter, group, accessKey, response.Header)
+	}
+	if err != nil {
+		ex.reportCredentials(accessKey, err)
+	}
+	return response.Body, err
+}
 
-	return HttpGetRequest(strUrl, MapValueEncodeURI(mapParams))
+func (ex *Exchange) ContractKeyGet(mapParams map[string]string, strRequestPath string) (string, error) {
+	return ex.ContractKeyGetWithContext(ex.Context(), mapParams, strRequestPath)
 }
 
-// 进行签名后的HTTP GET请求, 参考官方Python Demo写的
-// mapParams: map类型的请求参数, key:value
-// strRequest: API路由路径
-// return: 请求结果
-func (ex *Exchange) ApiKeyGet(mapParams map[string]string, strRequestPath string) string {
+// 同ContractKeyGet, 请求随ctx取消或超时
+func (ex *Exchange) ContractKeyGetWithContext(ctx context.Context, mapParams map[string]string, strRequestPath string) (string, error) {
 	strMethod := "GET"
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05")
-	//timestamp := "2020-09-12T08:45:34"
 
-	mapParams["AccessKeyId"] = ex.accessKey
-	mapParams["SignatureMethod"] = "HmacSHA256"
-	mapParams["SignatureVersion"] = "2"
-	mapParams["Timestamp"] = timestamp
-
-	hostName := HOST_NAME
-	mapParams["Signature"] = CreateSign(mapParams, strMethod, hostName, strRequestPath, ex.secretKey)
+	// 在副本上签名, 避免AccessKeyId和Signature出现在调用方的map中
+	mapParams = copyParams(mapParams)
+	hostName := ex.endpoints.HostContract
+	if err := ex.signParams(mapParams, strMethod, hostName, strRequestPath); err != nil {
+		return "", err
+	}
 
-	strUrl := TRADE_URL + strRequestPath
+	strUrl := ex.endpoints.ContractURL + strRequestPath
 
-	return HttpGetRequest(strUrl, MapValueEncodeURI(mapParams))
+	return ex.httpGet(ctx, strUrl, mapParams)
 }
 
 // 进行签名后的HTTP GET请求, 参考官方Python Demo写的
 // mapParams: map类型的请求参数, key:value
 // strRequest: API路由路径
 // return: 请求结果
-func (ex *Exchange) ApiKeyGetOrder(mapParams map[string]string, strRequestPath string) map[string]string {
+func (ex *Exchange) ApiKeyGet(mapParams map[string]string, strRequestPath string) (string, error) {
+	return ex.ApiKeyGetWithContext(ex.Context(), mapParams, strRequestPath)
+}
+
+// 同ApiKeyGet, 请求随ctx取消或超时
+func (ex *Exchange) ApiKeyGetWithContext(ctx context.Context, mapParams map[string]string, strRequestPath string) (string, error) {
 	strMethod := "GET"
 
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05")
-	//设置所在的时区
-	//var   timezone int
-	//timezone = 8
-	//t := time.Now()
-	//temp := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()-timezone, t.Minute(), t.Second(), t.Nanosecond(), time.Local)
-	//timestamp := temp.Format("2006-01-02T15:04:05")
+	// 在副本上签名, 避免AccessKeyId和Signature出现在调用方的map中
+	mapParams = copyParams(mapParams)
+	hostName := ex.endpoints.HostName
+	if err := ex.signParams(mapParams, strMethod, hostName, strRequestPath); err != nil {
+		return "", err
+	}
 
-	mapParams["AccessKeyId"] = ex.accessKey
-	mapParams["SignatureMethod"] = "HmacSHA256"
-	mapParams["SignatureVersion"] = "2"
-	mapParams["Timestamp"] = timestamp
+	strUrl := ex.endpoints.TradeURL + strRequestPath
 
-	hostName := HOST_NAME
+	return ex.httpGet(ctx, strUrl, mapParams)
+}
 
-	mapParams["Signature"] = CreateSign(mapParams, strMethod, hostName, strRequestPath, ex.secretKey)
+// 生成签名后的GET参数, 用于v1 WebSocket鉴权
+// mapParams: map类型的请求参数, key:value
+// strRequest: API路由路径
+// return: 签名后的参数
+func (ex *Exchange) ApiKeyGetOrder(mapParams map[string]string, strRequestPath string) (map[string]string, error) {
+	strMethod := "GET"
+
+	// 在副本上签名, 避免AccessKeyId和Signature出现在调用方的map中
+	mapParams = copyParams(mapParams)
+	hostName := ex.endpoints.HostName
+	if err := ex.signParams(mapParams, strMethod, hostName, strRequestPath); err != nil {
+		return nil, err
+	}
 
 	mapParams["op"] = "auth"
 	mapParams["type"] = "api"
 
-	return mapParams
+	return mapParams, nil
 }
-func (ex *Exchange) ApiKeyPostBatchorder(mapParams map[string]interface{}, strRequestPath string) string {
-	strMethod := "POST"
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05")
-
-	// t := time.Now()
-	// temp := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()-8, t.Minute(), t.Second(), t.Nanosecond(), time.Local)
-	// timestamp := temp.Format("2006-01-02T15:04:05")
-	// fmt.Println(t, ":timestamp:", timestamp)
 
-	mapParams2Sign := make(map[string]string)
-	mapParams2Sign["AccessKeyId"] = ex.accessKey
-	mapParams2Sign["SignatureMethod"] = "HmacSHA256"
-	mapParams2Sign["SignatureVersion"] = "2"
-	mapParams2Sign["Timestamp"] = timestamp
+// 进行签名后的HTTP POST请求, 请求体为任意JSON, 如批量下单的订单数组
+// params: 请求参数, 按JSON序列化后发送
+// strRequest: API路由路径
+// return: 请求结果
+func (ex *Exchange) ApiKeyPostBatchorder(params interface{}, strRequestPath string) (string, error) {
+	return ex.ApiKeyPostBatchorderWithContext(ex.Context(), params, strRequestPath)
+}
 
-	hostName := HOST_NAME
+// 同ApiKeyPostBatchorder, 请求随ctx取消或超时
+func (ex *Exchange) ApiKeyPostBatchorderWithContext(ctx context.Context, params interface{}, strRequestPath string) (string, error) {
+	strMethod := "POST"
 
-	mapParams2Sign["Signature"] = CreateSign(mapParams2Sign, strMethod, hostName, strRequestPath, ex.secretKey)
+	mapParams2Sign := make(map[string]string)
+	hostName := ex.endpoints.HostName
+	if err := ex.signParams(mapParams2Sign, strMethod, hostName, strRequestPath); err != nil {
+		return "", err
+	}
 
-	strUrl := TRADE_URL + strRequestPath + "?" + Map2UrlQuery(MapValueEncodeURI(mapParams2Sign))
+	strUrl := ex.endpoints.TradeURL + strRequestPath + "?" + EncodeParams(mapParams2Sign)
 
-	return HttpPostRequestBatchorder(strUrl, mapParams)
+	return ex.httpPost(ctx, strUrl, params)
 }
 
 // 进行签名后的HTTP POST请求, 参考官方Python Demo写的
 // mapParams: map类型的请求参数, key:value
 // strRequest: API路由路径
 // return: 请求结果
-func (ex *Exchange) ContractKeyPost(mapParams map[string]string, strRequestPath string) string {
+func (ex *Exchange) ContractKeyPost(mapParams map[string]string, strRequestPath string) (string, error) {
+	return ex.ContractKeyPostWithContext(ex.Context(), mapParams, strRequestPath)
+}
+
+// 同ContractKeyPost, 请求随ctx取消或超时
+func (ex *Exchange) ContractKeyPostWithContext(ctx context.Context, mapParams map[string]string, strRequestPath string) (string, error) {
 	strMethod := "POST"
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05")
-	// t := time.Now()
-	// temp := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()-6, t.Minute(), t.Second(), t.Nanosecond(), time.Local)
-	// timestamp := temp.Format("2006-01-02T15:04:05")
-	//fmt.Println(":timestamp:", timestamp)
 
 	mapParams2Sign := make(map[string]string)
-	mapParams2Sign["AccessKeyId"] = ex.accessKey
-	mapParams2Sign["SignatureMethod"] = "HmacSHA256"
-	mapParams2Sign["SignatureVersion"] = "2"
-	mapParams2Sign["Timestamp"] = timestamp
-
-	hostName := HOST_CONTRACT
-
-	mapParams2Sign["Signature"] = CreateSign(mapParams2Sign, strMethod, hostName, strRequestPath, ex.secretKey)
+	hostName := ex.endpoints.HostContract
+	if err := ex.signParams(mapParams2Sign, strMethod, hostName, strRequestPath); err != nil {
+		return "", err
+	}
 
-	strUrl := CONTRACT_URL + strRequestPath + "?" + Map2UrlQuery(MapValueEncodeURI(mapParams2Sign))
+	strUrl := ex.endpoints.ContractURL + strRequestPath + "?" + EncodeParams(mapParams2Sign)
 
-	return HttpPostRequest(strUrl, mapParams)
+	return ex.httpPost(ctx, strUrl, mapParams)
 }
 
 // 进行签名后的HTTP POST请求, 参考官方Python Demo写的
 // mapParams: map类型的请求参数, key:value
 // strRequest: API路由路径
 // return: 请求结果
-func (ex *Exchange) ApiKeyPost(mapParams map[string]string, strRequestPath string) string {
+func (ex *Exchange) ApiKeyPost(mapParams map[string]string, strRequestPath string) (string, error) {
+	return ex.ApiKeyPostWithContext(ex.Context(), mapParams, strRequestPath)
+}
+
+// 同ApiKeyPost, 请求随ctx取消或超时
+func (ex *Exchange) ApiKeyPostWithContext(ctx context.Context, mapParams map[string]string, strRequestPath string) (string, error) {
 	strMethod := "POST"
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05")
-	// t := time.Now()
-	// temp := time.Date(t.Year(), t.Month(), t.Day(), t.Hour()-6, t.Minute(), t.Second(), t.Nanosecond(), time.Local)
-	// timestamp := temp.Format("2006-01-02T15:04:05")
-	//fmt.Println(":timestamp:", timestamp)
 
 	mapParams2Sign := make(map[string]string)
-	mapParams2Sign["AccessKeyId"] = ex.accessKey
-	mapParams2Sign["SignatureMethod"] = "HmacSHA256"
-	mapParams2Sign["SignatureVersion"] = "2"
-	mapParams2Sign["Timestamp"] = timestamp
-
-	hostName := HOST_NAME
-
-	mapParams2Sign["Signature"] = CreateSign(mapParams2Sign, strMethod, hostName, strRequestPath, ex.secretKey)
+	hostName := ex.endpoints.HostName
+	if err := ex.signParams(mapParams2Sign, strMethod, hostName, strRequestPath); err != nil {
+		return "", err
+	}
 
-	strUrl := TRADE_URL + strRequestPath + "?" + Map2UrlQuery(MapValueEncodeURI(mapParams2Sign))
+	strUrl := ex.endpoints.TradeURL + strRequestPath + "?" + EncodeParams(mapParams2Sign)
 
-	return HttpPostRequest(strUrl, mapParams)
+	return ex.httpPost(ctx, strUrl, mapParams)
 }
 
-// 进行签名后的HTTP POST请求, 参考官方Python Demo写的
-// mapParams: map类型的请求参数, key:value
+// 生成签名后的POST参数, 用于v1 WebSocket鉴权
+// mapParams: 未使用, 保留以兼容旧的调用方式
 // strRequest: API路由路径
-// return: 请求结果
-func (ex *Exchange) ApiKeyPostOrder(mapParams map[string]string, strRequestPath string) map[string]string {
+// return: 签名后的参数
+func (ex *Exchange) ApiKeyPostOrder(mapParams map[string]string, strRequestPath string) (map[string]string, error) {
 	strMethod := "POST"
-	timestamp := time.Now().UTC().Format("2006-01-02T15:04:05")
-	/*
-			 "op": "auth",
-		  "type": "api",
-
-
-
-
-	*/
 
 	mapParams2Sign := make(map[string]string)
-	mapParams2Sign["AccessKeyId"] = ex.accessKey
-	mapParams2Sign["SignatureMethod"] = "HmacSHA256"
-	mapParams2Sign["SignatureVersion"] = "2"
-	mapParams2Sign["Timestamp"] = timestamp
 	mapParams2Sign["op"] = "auth"
 	mapParams2Sign["type"] = "api"
-	hostName := HOST_NAME
-
-	mapParams2Sign["Signature"] = CreateSign(mapParams2Sign, strMethod, hostName, strRequestPath, ex.secretKey)
+	hostName := ex.endpoints.HostName
+	if err := ex.signParams(mapParams2Sign, strMethod, hostName, strRequestPath); err != nil {
+		return nil, err
+	}
 
-	//strUrl := TRADE_URL + strRequestPath + "?" + Map2UrlQuery(MapValueEncodeURI(mapParams2Sign))
-	return mapParams2Sign
+	return mapParams2Sign, nil
+}
 
+// 填入AccessKeyId等签名公共参数, 并用Exchange的Signer计算Signature
+// mapParams: 参与签名的参数, 签名结果写入其中
+// strMethod: 请求的方法 GET, POST......
+// strHostUrl: 请求的主机
+// strRequestPath: 请求的路由路径
+func (ex *Exchange) signParams(mapParams map[string]string, strMethod, strHostUrl, strRequestPath string) error {
+	return ex.withSigner(func(accessKey string, signer Signer) error {
+		mapParams["AccessKeyId"] = accessKey
+		mapParams["SignatureMethod"] = signer.SignatureMethod()
+		mapParams["SignatureVersion"] = "2"
+		mapParams["Timestamp"] = ex.timestamp()
+
+		signature, err := signer.Sign(CreatePayload(mapParams, strMethod, strHostUrl, strRequestPath))
+		if err != nil {
+			return err
+		}
+		mapParams["Signature"] = signature
+		return nil
+	})
 }
 
 // 构造签名
@@ -300,30 +425,56 @@ func (ex *Exchange) ApiKeyPostOrder(mapParams map[string]string, strRequestPath
 // strRequestPath: 请求的路由路径
 // strSecretKey: 进行签名的密钥
 func CreateSign(mapParams map[string]string, strMethod, strHostUrl, strRequestPath, strSecretKey string) string {
+	return ComputeHmac256(CreatePayload(mapParams, strMethod, strHostUrl, strRequestPath), strSecretKey)
+}
+
+// 构造待签名的字符串, 各签名方式共用
+// mapParams: 送进来参与签名的参数, 不含Signature
+// strMethod: 请求的方法 GET, POST......
+// strHostUrl: 请求的主机
+// strRequestPath: 请求的路由路径
+func CreatePayload(mapParams map[string]string, strMethod, strHostUrl, strRequestPath string) string {
 	// 参数处理, 按API要求, 参数名应按ASCII码进行排序(使用UTF-8编码, 其进行URI编码, 16进制字符必须大写)
-	mapCloned := make(map[string]string)
-	for key, value := range mapParams {
-		mapCloned[key] = url.QueryEscape(value)
-	}
+	strParams := EncodeParams(mapParams)
 
-	strParams := Map2UrlQueryBySort(mapCloned)
+	return strMethod + "\n" + strHostUrl + "\n" + strRequestPath + "\n" + strParams
+}
 
-	strPayload := strMethod + "\n" + strHostUrl + "\n" + strRequestPath + "\n" + strParams
-	return ComputeHmac256(strPayload, strSecretKey)
+// 按火币签名规范构造查询字符串, 签名和请求地址都使用它, 保证两者一致
+// 参数名按ASCII码升序排列, 参数值按RFC 3986进行URI编码, 16进制字符大写, 空格编码为%20
+// 不修改传入的map
+// mapParams: 原始的参数键值对, 值未经编码
+// return: 查询字符串, 如 AccessKeyId=xxx&Timestamp=2017-05-11T15%3A19%3A30
+func EncodeParams(mapParams map[string]string) string {
+	keys := make([]string, 0, len(mapParams))
+	for key := range mapParams {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		pairs = append(pairs, EscapeURI(key)+"="+EscapeURI(mapParams[key]))
+	}
+	return strings.Join(pairs, "&")
 }
 
-//func ContractSign(mapParams map[string]string, strMethod, strHostUrl, strRequestPath, strSecretKey string) string {
-//	// 参数处理, 按API要求, 参数名应按ASCII码进行排序(使用UTF-8编码, 其进行URI编码, 16进制字符必须大写)
-//	mapCloned := make(map[string]string)
-//	for key, value := range mapParams {
-//		mapCloned[key] = url.QueryEscape(value)
-//	}
-//
-//	strParams := Map2UrlQueryBySort(mapCloned)
-//
-//	strPayload := strMethod + "\n" + strHostUrl + "\n" + strRequestPath + "\n" + strParams
-//	return ComputeHmac256(strPayload, strSecretKey)
-//}
+// 按RFC 3986进行URI编码, 仅保留字母、数字和 -_.~ , 其余字节编码为大写的%XX
+func EscapeURI(value string) string {
+	const upperhex = "0123456789ABCDEF"
+	var builder strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || c == '-' || c == '_' || c == '.' || c == '~' {
+			builder.WriteByte(c)
+			continue
+		}
+		builder.WriteByte('%')
+		builder.WriteByte(upperhex[c>>4])
+		builder.WriteByte(upperhex[c&15])
+	}
+	return builder.String()
+}
 
 // 对Map按着ASCII码进行排序
 // mapValue: 需要进行排序的map
@@ -343,53 +494,39 @@ func MapSortByKey(mapValue map[string]string) map[string]string {
 	return mapReturn
 }
 
-// 对Map的值进行URI编码
+// 对Map的值进行URI编码, 不修改传入的map
 // mapParams: 需要进行URI编码的map
-// return: 编码后的map
+// return: 编码后的新map
 func MapValueEncodeURI(mapValue map[string]string) map[string]string {
+	mapReturn := make(map[string]string, len(mapValue))
 	for key, value := range mapValue {
-		valueEncodeURI := url.QueryEscape(value)
-		mapValue[key] = valueEncodeURI
+		mapReturn[key] = EscapeURI(value)
 	}
-	return mapValue
+	return mapReturn
 }
 
-// 将map格式的请求参数转换为字符串格式的
+// 将map格式的请求参数转换为字符串格式的, 按照Map的key升序排列, 值不做编码
 // mapParams: map格式的参数键值对
 // return: 查询字符串
 func Map2UrlQuery(mapParams map[string]string) string {
-	var strParams string
-	for key, value := range mapParams {
-		strParams += (key + "=" + value + "&")
-	}
-
-	if 0 < len(strParams) {
-		strParams = string([]rune(strParams)[:len(strParams)-1])
-	}
-
-	return strParams
+	return Map2UrlQueryBySort(mapParams)
 }
 
-// 将map格式的请求参数转换为字符串格式的,并按照Map的key升序排列
+// 将map格式的请求参数转换为字符串格式的,并按照Map的key升序排列, 值不做编码
 // mapParams: map格式的参数键值对
 // return: 查询字符串
 func Map2UrlQueryBySort(mapParams map[string]string) string {
-	var keys []string
+	keys := make([]string, 0, len(mapParams))
 	for key := range mapParams {
 		keys = append(keys, key)
 	}
 	sort.Strings(keys)
 
-	var strParams string
+	pairs := make([]string, 0, len(keys))
 	for _, key := range keys {
-		strParams += key + "=" + mapParams[key] + "&"
+		pairs = append(pairs, key+"="+mapParams[key])
 	}
-
-	if 0 < len(strParams) {
-		strParams = string([]rune(strParams)[:len(strParams)-1])
-	}
-
-	return strParams
+	return strings.Join(pairs, "&")
 }
 
 // HMAC SHA256加密
@@ -397,9 +534,20 @@ func Map2UrlQueryBySort(mapParams map[string]string) string {
 // strSecret: 密钥
 // return: BASE64编码的密文
 func ComputeHmac256(strMessage string, strSecret string) string {
-	key := []byte(strSecret)
+	return computeHmac256(strMessage, []byte(strSecret))
+}
+
+func computeHmac256(strMessage string, key []byte) string {
 	h := hmac.New(sha256.New, key)
 	h.Write([]byte(strMessage))
 
 	return base64.StdEncoding.EncodeToString(h.Sum(nil))
 }
+
+func copyParams(mapParams map[string]string) map[string]string {
+	mapCloned := make(map[string]string, len(mapParams))
+	for key, value := range mapParams {
+		mapCloned[key] = value
+	}
+	return mapCloned
+}
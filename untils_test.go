@@ -0,0 +1,89 @@
+package huobi
+
+import (
+	"reflect"
+	"testing"
+)
+
+// 火币API文档"签名认证"一节的示例
+// 文档中的AccessKey和SecretKey已打码, 签名值由打码后的密钥计算, 并用Python的hmac模块独立核对
+var docSignParams = map[string]string{
+	"AccessKeyId":      "e2xxxxxx-99xxxxxx-84xxxxxx-7xxxx",
+	"SignatureMethod":  "HmacSHA256",
+	"SignatureVersion": "2",
+	"Timestamp":        "2017-05-11T15:19:30",
+	"order-id":         "1234567890",
+}
+
+const (
+	docSecretKey = "b0xxxxxx-c6xxxxxx-94xxxxxx-dxxxx"
+	docPayload   = "GET\napi.huobi.pro\n/v1/order/orders\n" +
+		"AccessKeyId=e2xxxxxx-99xxxxxx-84xxxxxx-7xxxx&SignatureMethod=HmacSHA256&SignatureVersion=2&Timestamp=2017-05-11T15%3A19%3A30&order-id=1234567890"
+	docSignature = "Nmd8AU8uAe0mkFpxNbiava0aeZzBEtYjCdie1ZYZjoM="
+)
+
+func TestCreatePayloadDocExample(t *testing.T) {
+	payload := CreatePayload(docSignParams, "GET", "api.huobi.pro", "/v1/order/orders")
+	if payload != docPayload {
+		t.Errorf("payload = %q, want %q", payload, docPayload)
+	}
+}
+
+func TestCreateSignDocExample(t *testing.T) {
+	signature := CreateSign(docSignParams, "GET", "api.huobi.pro", "/v1/order/orders", docSecretKey)
+	if signature != docSignature {
+		t.Errorf("signature = %s, want %s", signature, docSignature)
+	}
+}
+
+func TestEncodeParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		params map[string]string
+		want   string
+	}{
+		{"empty", map[string]string{}, ""},
+		{"ascii order", map[string]string{"symbol": "btcusdt", "Timestamp": "t", "AccessKeyId": "a", "account-id": "1"},
+			"AccessKeyId=a&Timestamp=t&account-id=1&symbol=btcusdt"},
+		{"uppercase hex", map[string]string{"Timestamp": "2017-05-11T15:19:30", "path": "/v1/order"},
+			"Timestamp=2017-05-11T15%3A19%3A30&path=%2Fv1%2Forder"},
+		{"space", map[string]string{"note": "a b"}, "note=a%20b"},
+		{"reserved", map[string]string{"v": "a+b=c&d~e_f.g-h"}, "v=a%2Bb%3Dc%26d~e_f.g-h"},
+		{"utf-8", map[string]string{"v": "火币"}, "v=%E7%81%AB%E5%B8%81"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := EncodeParams(test.params); got != test.want {
+				t.Errorf("EncodeParams = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSignDoesNotModifyParams(t *testing.T) {
+	params := map[string]string{"Timestamp": "2017-05-11T15:19:30", "note": "a b"}
+	want := map[string]string{"Timestamp": "2017-05-11T15:19:30", "note": "a b"}
+
+	EncodeParams(params)
+	CreatePayload(params, "GET", "api.huobi.pro", "/v1/order/orders")
+	CreateSign(params, "GET", "api.huobi.pro", "/v1/order/orders", docSecretKey)
+	if !reflect.DeepEqual(params, want) {
+		t.Errorf("params changed to %v, want %v", params, want)
+	}
+}
+
+func TestApiKeyGetOrderDoesNotModifyParams(t *testing.T) {
+	ex := newExchange("ak", "sk")
+	params := map[string]string{"symbol": "btcusdt"}
+
+	signed, err := ex.ApiKeyGetOrder(params, "/ws/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(params, map[string]string{"symbol": "btcusdt"}) {
+		t.Errorf("params changed to %v", params)
+	}
+	if signed["AccessKeyId"] != "ak" || signed["Signature"] == "" || signed["symbol"] != "btcusdt" || signed["op"] != "auth" {
+		t.Errorf("signed params = %v", signed)
+	}
+}
@@ -0,0 +1,206 @@
+package huobi
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/shopspring/decimal"
+)
+
+// 下单参数不符合交易对规则, 具体原因见OrderValidationError
+var ErrInvalidOrder = errors.New("huobi: invalid order")
+
+// 下单参数校验失败的原因
+type OrderValidationError struct {
+	Symbol string          // 交易对
+	Field  string          // 不符合规则的参数, 如 amount, price, value, state
+	Reason string          // 原因, 如 below min, above max, too many decimals
+	Value  decimal.Decimal // 参数值
+	Limit  decimal.Decimal // 规则的限制值
+}
+
+func (e *OrderValidationError) Error() string {
+	if e.Field == "state" {
+		return fmt.Sprintf("huobi: invalid order %s: %s", e.Symbol, e.Reason)
+	}
+	return fmt.Sprintf("huobi: invalid order %s: %s %s %s %s", e.Symbol, e.Field, e.Value, e.Reason, e.Limit)
+}
+
+func (e *OrderValidationError) Unwrap() error {
+	return ErrInvalidOrder
+}
+
+// 下单前按交易对规则校验下单参数, 不发出网络请求
+// 规则取自/v1/common/symbols: 交易状态、价格和数量精度、最小最大下单量、最小下单金额
+// 设置PriceBand后还检查价格是否偏离参考价过多
+type OrderValidator struct {
+	// 为true时把价格和数量调整到交易对精度后再校验, 而不是因精度不符返回错误
+	// 买单价格向下取整, 卖单价格向上取整, 数量向下截断
+	Adjust bool
+	// 限价单价格相对参考价的最大偏离比例, 如0.1表示买价不高于参考价的110%, 卖价不低于参考价的90%, 为0时不检查
+	PriceBand decimal.Decimal
+	// 参考价, 为nil时Exchange使用最新成交价(GetTicker)
+	ReferencePrice func(symbol string) (decimal.Decimal, error)
+}
+
+// 下单时使用的校验器, 设置后Place在发出请求前校验下单参数
+func WithOrderValidator(validator *OrderValidator) Option {
+	return func(ex *Exchange) {
+		ex.validator = validator
+	}
+}
+
+// 按交易对规则校验下单参数, 返回调整后的下单参数副本
+func (v *OrderValidator) Validate(symbol *SymbolsData, params *PlaceRequestParams) (*PlaceRequestParams, error) {
+	order := *params
+	name := symbol.Name()
+	if !symbol.IsTradable() {
+		return nil, &OrderValidationError{Symbol: name, Field: "state",
+			Reason: "symbol not tradable, state " + symbol.State + ", api-trading " + symbol.ApiTrading}
+	}
+
+	buy := order.Type.IsBuy()
+	market := order.Type.IsMarket()
+
+	if v.Adjust {
+		if market && buy {
+			// 市价买单的amount为金额, 只按金额精度截断
+			order.Amount = order.Amount.Truncate(int32(symbol.ValuePrecision))
+		} else {
+			order.Amount = symbol.TruncAmount(order.Amount)
+		}
+		if !market {
+			order.Price = adjustPrice(order.Price, symbol.PricePrecision, buy)
+		}
+		if order.Type.IsStop() {
+			order.StopPrice = symbol.TruncPrice(order.StopPrice)
+		}
+	}
+
+	if !order.Amount.IsPositive() {
+		return nil, &OrderValidationError{Symbol: name, Field: "amount", Reason: "must be above", Value: order.Amount}
+	}
+
+	if market {
+		if buy {
+			// 市价买单的amount为下单金额
+			if err := checkPrecision(name, "value", order.Amount, symbol.ValuePrecision); err != nil {
+				return nil, err
+			}
+			if err := checkRange(name, "value", order.Amount, symbol.MinOrderValue, symbol.BuyMarketMaxOrderValue); err != nil {
+				return nil, err
+			}
+			return &order, nil
+		}
+		if err := checkPrecision(name, "amount", order.Amount, symbol.AmountPrecision); err != nil {
+			return nil, err
+		}
+		if err := checkRange(name, "amount", order.Amount, symbol.SellMarketMinOrderAmt, symbol.SellMarketMaxOrderAmt); err != nil {
+			return nil, err
+		}
+		return &order, nil
+	}
+
+	if !order.Price.IsPositive() {
+		return nil, &OrderValidationError{Symbol: name, Field: "price", Reason: "must be above", Value: order.Price}
+	}
+	if err := checkPrecision(name, "price", order.Price, symbol.PricePrecision); err != nil {
+		return nil, err
+	}
+	if order.Type.IsStop() {
+		if err := checkPrecision(name, "stop-price", order.StopPrice, symbol.PricePrecision); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkPrecision(name, "amount", order.Amount, symbol.AmountPrecision); err != nil {
+		return nil, err
+	}
+	if err := checkRange(name, "amount", order.Amount, symbol.LimitOrderMinOrderAmt, symbol.LimitOrderMaxOrderAmt); err != nil {
+		return nil, err
+	}
+	if err := checkRange(name, "value", order.Price.Mul(order.Amount), symbol.MinOrderValue, symbol.MaxOrderValue); err != nil {
+		return nil, err
+	}
+	if v.PriceBand.IsPositive() && v.ReferencePrice != nil {
+		reference, err := v.ReferencePrice(name)
+		if err != nil {
+			return nil, err
+		}
+		if err := checkPriceBand(name, order.Price, reference, v.PriceBand, buy); err != nil {
+			return nil, err
+		}
+	}
+	return &order, nil
+}
+
+// 把价格调整到精度, 买单向下, 卖单向上, 避免以更差的价格成交
+func adjustPrice(price decimal.Decimal, precision int, buy bool) decimal.Decimal {
+	shift := decimal.New(1, int32(precision))
+	if buy {
+		return price.Mul(shift).Floor().Div(shift)
+	}
+	return price.Mul(shift).Ceil().Div(shift)
+}
+
+func checkPrecision(symbol, field string, value decimal.Decimal, precision int) error {
+	if !value.Equal(value.Truncate(int32(precision))) {
+		return &OrderValidationError{Symbol: symbol, Field: field, Reason: "has more decimals than precision",
+			Value: value, Limit: decimal.New(int64(precision), 0)}
+	}
+	return nil
+}
+
+// 检查value是否在[min, max]内, min或max为0时不检查
+func checkRange(symbol, field string, value, min, max decimal.Decimal) error {
+	if min.IsPositive() && value.LessThan(min) {
+		return &OrderValidationError{Symbol: symbol, Field: field, Reason: "below min", Value: value, Limit: min}
+	}
+	if max.IsPositive() && value.GreaterThan(max) {
+		return &OrderValidationError{Symbol: symbol, Field: field, Reason: "above max", Value: value, Limit: max}
+	}
+	return nil
+}
+
+// 买价不能高于参考价的(1+band)倍, 卖价不能低于参考价的(1-band)倍
+func checkPriceBand(symbol string, price, reference, band decimal.Decimal, buy bool) error {
+	if !reference.IsPositive() {
+		return nil
+	}
+	one := decimal.New(1, 0)
+	if buy {
+		if limit := reference.Mul(one.Add(band)); price.GreaterThan(limit) {
+			return &OrderValidationError{Symbol: symbol, Field: "price", Reason: "above price band", Value: price, Limit: limit}
+		}
+		return nil
+	}
+	if limit := reference.Mul(one.Sub(band)); price.LessThan(limit) {
+		return &OrderValidationError{Symbol: symbol, Field: "price", Reason: "below price band", Value: price, Limit: limit}
+	}
+	return nil
+}
+
+// 按交易对规则校验下单参数, 返回调整后的下单参数副本
+// 未通过WithOrderValidator设置校验器时按默认规则校验, 不调整精度, 不检查价格偏离
+func (ex *Exchange) ValidateOrder(params *PlaceRequestParams) (*PlaceRequestParams, error) {
+	symbol, ok := ex.symbols.Get(params.Symbol)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownSymbol, params.Symbol)
+	}
+	validator := OrderValidator{}
+	if ex.validator != nil {
+		validator = *ex.validator
+	}
+	if validator.PriceBand.IsPositive() && validator.ReferencePrice == nil {
+		validator.ReferencePrice = ex.lastPrice
+	}
+	return validator.Validate(symbol, params)
+}
+
+// 最新成交价, 作为默认的参考价
+func (ex *Exchange) lastPrice(symbol string) (decimal.Decimal, error) {
+	tickerReturn, err := ex.GetTicker(symbol)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return tickerReturn.GetPrice(), nil
+}
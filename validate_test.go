@@ -0,0 +1,67 @@
+package huobi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestOrderValidatorAdjust(t *testing.T) {
+	symbol := &SymbolsData{Symbol: "htusdt", State: "online", PricePrecision: 4, AmountPrecision: 0, ValuePrecision: 8}
+	validator := &OrderValidator{Adjust: true}
+	d := decimal.RequireFromString
+
+	tests := []struct {
+		name   string
+		params PlaceRequestParams
+		amount string
+		price  string
+	}{
+		{"buy-market keeps value decimals", PlaceRequestParams{Symbol: "htusdt", Type: OrderTypeBuyMarket, Amount: d("10.123456789")}, "10.12345678", "0"},
+		{"sell-market truncates amount", PlaceRequestParams{Symbol: "htusdt", Type: OrderTypeSellMarket, Amount: d("10.9")}, "10", "0"},
+		{"buy-limit floors price", PlaceRequestParams{Symbol: "htusdt", Type: OrderTypeBuyLimit, Amount: d("3.7"), Price: d("1.00009")}, "3", "1"},
+		{"sell-limit ceils price", PlaceRequestParams{Symbol: "htusdt", Type: OrderTypeSellLimit, Amount: d("3.7"), Price: d("1.00001")}, "3", "1.0001"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order, err := validator.Validate(symbol, &test.params)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !order.Amount.Equal(d(test.amount)) || !order.Price.Equal(d(test.price)) {
+				t.Errorf("amount, price = %s, %s, want %s, %s", order.Amount, order.Price, test.amount, test.price)
+			}
+		})
+	}
+}
+
+func TestOrderValidatorRejects(t *testing.T) {
+	symbol := &SymbolsData{Symbol: "btcusdt", State: "online", PricePrecision: 2, AmountPrecision: 4, ValuePrecision: 8,
+		LimitOrderMinOrderAmt: decimal.New(1, -4), MinOrderValue: decimal.New(5, 0)}
+	validator := &OrderValidator{}
+	d := decimal.RequireFromString
+
+	tests := []struct {
+		name   string
+		params PlaceRequestParams
+		field  string
+	}{
+		{"price decimals", PlaceRequestParams{Symbol: "btcusdt", Type: OrderTypeBuyLimit, Amount: d("1"), Price: d("100.001")}, "price"},
+		{"amount decimals", PlaceRequestParams{Symbol: "btcusdt", Type: OrderTypeBuyLimit, Amount: d("0.00001"), Price: d("100")}, "amount"},
+		{"min value", PlaceRequestParams{Symbol: "btcusdt", Type: OrderTypeBuyLimit, Amount: d("0.01"), Price: d("100")}, "value"},
+		{"market buy value decimals", PlaceRequestParams{Symbol: "btcusdt", Type: OrderTypeBuyMarket, Amount: d("10.123456789")}, "value"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			order, err := validator.Validate(symbol, &test.params)
+			var validationErr *OrderValidationError
+			if order != nil || !errors.As(err, &validationErr) || !errors.Is(err, ErrInvalidOrder) {
+				t.Fatalf("Validate = %v, %v, want OrderValidationError", order, err)
+			}
+			if validationErr.Field != test.field {
+				t.Errorf("field = %s, want %s", validationErr.Field, test.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package huobi
+
+// 资产和订单WebSocket v2的路径
+const WS_V2_PATH = "/ws/v2"
+
+// WebSocket v2鉴权参数, 签名版本2.1
+type WSAuthParams struct {
+	AuthType         string `json:"authType"`         // 固定为api
+	AccessKey        string `json:"accessKey"`        // API Key
+	SignatureMethod  string `json:"signatureMethod"`  // HmacSHA256
+	SignatureVersion string `json:"signatureVersion"` // 固定为2.1
+	Timestamp        string `json:"timestamp"`        // UTC时间, 如 2019-09-01T18:16:16
+	Signature        string `json:"signature"`        // 签名
+}
+
+// WebSocket v2鉴权消息, 序列化后直接发送
+// {"action":"req","ch":"auth","params":{...}}
+type WSAuthRequest struct {
+	Action string       `json:"action"`
+	Ch     string       `json:"ch"`
+	Params WSAuthParams `json:"params"`
+}
+
+// 按签名版本2.1生成WebSocket v2鉴权消息
+// authType不参与签名, 参与签名的是accessKey, signatureMethod, signatureVersion, timestamp四个参数
+// signer: 签名方式, HmacSHA256时为NewHmacSigner(secretKey)
+// accessKey: API Key
+// strHostUrl: WebSocket主机, 如 api.huobi.pro
+// timestamp: UTC时间, 格式 2006-01-02T15:04:05
+func CreateWSAuthV2(signer Signer, accessKey, strHostUrl, timestamp string) (*WSAuthRequest, error) {
+	mapParams := make(map[string]string)
+	mapParams["accessKey"] = accessKey
+	mapParams["signatureMethod"] = signer.SignatureMethod()
+	mapParams["signatureVersion"] = "2.1"
+	mapParams["timestamp"] = timestamp
+
+	signature, err := signer.Sign(CreatePayload(mapParams, "GET", strHostUrl, WS_V2_PATH))
+	if err != nil {
+		return nil, err
+	}
+
+	return &WSAuthRequest{
+		Action: "req",
+		Ch:     "auth",
+		Params: WSAuthParams{
+			AuthType:         "api",
+			AccessKey:        accessKey,
+			SignatureMethod:  mapParams["signatureMethod"],
+			SignatureVersion: mapParams["signatureVersion"],
+			Timestamp:        timestamp,
+			Signature:        signature,
+		},
+	}, nil
+}
+
+// 用Exchange的密钥、签名方式和对时后的时间生成WebSocket v2鉴权消息
+func (ex *Exchange) WSAuthV2() (*WSAuthRequest, error) {
+	var authRequest *WSAuthRequest
+	err := ex.withSigner(func(accessKey string, signer Signer) error {
+		var err error
+		authRequest, err = CreateWSAuthV2(signer, accessKey, ex.endpoints.HostName, ex.timestamp())
+		return err
+	})
+	return authRequest, err
+}
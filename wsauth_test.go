@@ -0,0 +1,60 @@
+package huobi
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+// 火币WebSocket v2文档"鉴权"一节的示例, 文档未给出SecretKey, 使用签名认证示例中打码后的密钥
+// 签名值用Python的hmac模块独立计算
+const (
+	docWSAccessKey = "0664b695-rtgrfhyy-9ccb1acd-8b4b5"
+	docWSTimestamp = "2019-09-01T18:16:16"
+	docWSPayload   = "GET\napi.huobi.pro\n/ws/v2\n" +
+		"accessKey=0664b695-rtgrfhyy-9ccb1acd-8b4b5&signatureMethod=HmacSHA256&signatureVersion=2.1&timestamp=2019-09-01T18%3A16%3A16"
+	docWSSignature = "MMAixxE2hjbTEukhrr/ymmWsf0+1+/y6sZN+jygw1Yw="
+)
+
+// 记录待签名字符串的Signer
+type recordSigner struct {
+	payload string
+}
+
+func (s *recordSigner) SignatureMethod() string {
+	return "HmacSHA256"
+}
+
+func (s *recordSigner) Sign(payload string) (string, error) {
+	s.payload = payload
+	return "signature", nil
+}
+
+func TestCreateWSAuthV2Payload(t *testing.T) {
+	signer := &recordSigner{}
+	if _, err := CreateWSAuthV2(signer, docWSAccessKey, "api.huobi.pro", docWSTimestamp); err != nil {
+		t.Fatal(err)
+	}
+	if signer.payload != docWSPayload {
+		t.Errorf("payload = %q, want %q", signer.payload, docWSPayload)
+	}
+}
+
+func TestCreateWSAuthV2DocExample(t *testing.T) {
+	authRequest, err := CreateWSAuthV2(NewHmacSigner(docSecretKey), docWSAccessKey, "api.huobi.pro", docWSTimestamp)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if authRequest.Params.Signature != docWSSignature {
+		t.Errorf("signature = %s, want %s", authRequest.Params.Signature, docWSSignature)
+	}
+
+	bytes, err := json.Marshal(authRequest)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"action":"req","ch":"auth","params":{"authType":"api","accessKey":"0664b695-rtgrfhyy-9ccb1acd-8b4b5",` +
+		`"signatureMethod":"HmacSHA256","signatureVersion":"2.1","timestamp":"2019-09-01T18:16:16","signature":"` + docWSSignature + `"}}`
+	if string(bytes) != want {
+		t.Errorf("auth message = %s, want %s", bytes, want)
+	}
+}